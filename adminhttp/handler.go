@@ -0,0 +1,198 @@
+// Package adminhttp exposes read-only operational endpoints for services
+// built on tars: configured providers and their health, loaded templates,
+// usage statistics, cache statistics, and recent errors.
+package adminhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/bpradana/tars/config"
+	"github.com/bpradana/tars/llm"
+)
+
+// CacheStats describes the effectiveness of a response cache.
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+}
+
+// CacheReporter is implemented by caches that report their statistics.
+type CacheReporter interface {
+	CacheStats() CacheStats
+}
+
+// options contains the sources served by the handler.
+type options struct {
+	app       *config.App
+	providers map[string]llm.BaseProvider
+	stats     *Stats
+	caches    map[string]CacheReporter
+}
+
+// Option is a function type that adds a source to the admin handler.
+type Option func(*options)
+
+// WithApp serves the providers and templates of a configured App.
+//
+// Example:
+//
+//	handler := adminhttp.NewHandler(adminhttp.WithApp(app))
+func WithApp(app *config.App) Option {
+	return func(o *options) {
+		o.app = app
+	}
+}
+
+// WithProvider serves a provider that is not part of a configured App.
+//
+// Example:
+//
+//	handler := adminhttp.NewHandler(adminhttp.WithProvider("openai", provider))
+func WithProvider(name string, provider llm.BaseProvider) Option {
+	return func(o *options) {
+		o.providers[name] = provider
+	}
+}
+
+// WithStats serves usage statistics and recent errors collected by a Stats sink.
+//
+// Example:
+//
+//	handler := adminhttp.NewHandler(adminhttp.WithStats(stats))
+func WithStats(stats *Stats) Option {
+	return func(o *options) {
+		o.stats = stats
+	}
+}
+
+// WithCache serves the statistics of a named cache.
+//
+// Example:
+//
+//	handler := adminhttp.NewHandler(adminhttp.WithCache("responses", cache))
+func WithCache(name string, cache CacheReporter) Option {
+	return func(o *options) {
+		o.caches[name] = cache
+	}
+}
+
+// ProviderStatus describes a provider and its current health.
+type ProviderStatus struct {
+	Name           string     `json:"name"`
+	Provider       string     `json:"provider"`
+	Healthy        bool       `json:"healthy"`
+	UnhealthyUntil *time.Time `json:"unhealthy_until,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+}
+
+// NewHandler creates an http.Handler serving the read-only admin endpoints:
+//
+//	GET /providers  configured providers and their health
+//	GET /templates  the template manifest of the App
+//	GET /usage      aggregated usage per provider
+//	GET /cache      cache statistics
+//	GET /errors     recent errors, newest first
+//
+// Endpoints whose source was not configured respond with 404. The handler
+// performs no authentication, so it should be mounted on an internal
+// listener or behind the service's own auth middleware.
+//
+// Example:
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", adminhttp.NewHandler(
+//	  adminhttp.WithApp(app),
+//	  adminhttp.WithStats(stats),
+//	)))
+func NewHandler(opts ...Option) http.Handler {
+	o := options{
+		providers: make(map[string]llm.BaseProvider),
+		caches:    make(map[string]CacheReporter),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /providers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, o.providerStatuses())
+	})
+	mux.HandleFunc("GET /templates", func(w http.ResponseWriter, r *http.Request) {
+		if o.app == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, o.app.Manifest())
+	})
+	mux.HandleFunc("GET /usage", func(w http.ResponseWriter, r *http.Request) {
+		if o.stats == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, o.stats.Usage())
+	})
+	mux.HandleFunc("GET /cache", func(w http.ResponseWriter, r *http.Request) {
+		if len(o.caches) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		stats := make(map[string]CacheStats, len(o.caches))
+		for name, cache := range o.caches {
+			stats[name] = cache.CacheStats()
+		}
+		writeJSON(w, stats)
+	})
+	mux.HandleFunc("GET /errors", func(w http.ResponseWriter, r *http.Request) {
+		if o.stats == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, o.stats.RecentErrors())
+	})
+	return mux
+}
+
+// providerStatuses returns the status of every provider, sorted by name.
+// Providers registered with WithProvider take precedence over App providers
+// of the same name.
+func (o options) providerStatuses() []ProviderStatus {
+	providers := make(map[string]llm.BaseProvider)
+	if o.app != nil {
+		for name, provider := range o.app.Providers() {
+			providers[name] = provider
+		}
+	}
+	for name, provider := range o.providers {
+		providers[name] = provider
+	}
+
+	statuses := make([]ProviderStatus, 0, len(providers))
+	for name, provider := range providers {
+		status := ProviderStatus{Name: name, Provider: provider.GetName(), Healthy: true}
+		if health, ok := llm.HealthOf(provider); ok {
+			status.Healthy = health.Healthy
+			status.Reason = health.Reason
+			if !health.Healthy {
+				status.UnhealthyUntil = &health.UnhealthyUntil
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,119 @@
+package adminhttp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/export"
+)
+
+// ProviderUsage aggregates the invocations served by a single provider.
+type ProviderUsage struct {
+	Provider         string        `json:"provider"`
+	Requests         int           `json:"requests"`
+	Errors           int           `json:"errors"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	TotalTokens      int           `json:"total_tokens"`
+	TotalLatency     time.Duration `json:"total_latency"`
+	LastRequest      time.Time     `json:"last_request"`
+}
+
+// ErrorRecord is a failed invocation kept for inspection.
+type ErrorRecord struct {
+	Time              time.Time `json:"time"`
+	Provider          string    `json:"provider"`
+	PromptFingerprint string    `json:"prompt_fingerprint"`
+	Error             string    `json:"error"`
+}
+
+// Stats is an export.Sink that aggregates usage per provider and keeps the
+// most recent errors in memory, for serving on the admin endpoints.
+//
+// Example:
+//
+//	stats := adminhttp.NewStats(100)
+//	exporter := export.NewExporter(stats, export.WithFlushInterval(time.Second))
+//	provider := export.Wrap(llm.NewOpenAI(llm.WithAPIKey(key)), exporter)
+type Stats struct {
+	mu        sync.RWMutex
+	usage     map[string]*ProviderUsage
+	errors    []ErrorRecord
+	maxErrors int
+}
+
+// NewStats creates a Stats sink that keeps up to maxErrors recent errors.
+// A maxErrors of zero or less keeps no errors.
+func NewStats(maxErrors int) *Stats {
+	return &Stats{
+		usage:     make(map[string]*ProviderUsage),
+		maxErrors: max(maxErrors, 0),
+	}
+}
+
+// Write implements export.Sink.
+func (s *Stats) Write(ctx context.Context, events []export.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		usage, ok := s.usage[event.Provider]
+		if !ok {
+			usage = &ProviderUsage{Provider: event.Provider}
+			s.usage[event.Provider] = usage
+		}
+
+		usage.Requests++
+		usage.PromptTokens += event.PromptTokens
+		usage.CompletionTokens += event.CompletionTokens
+		usage.TotalTokens += event.TotalTokens
+		usage.TotalLatency += event.Latency
+		if event.Time.After(usage.LastRequest) {
+			usage.LastRequest = event.Time
+		}
+
+		if event.Error == "" {
+			continue
+		}
+		usage.Errors++
+		s.errors = append(s.errors, ErrorRecord{
+			Time:              event.Time,
+			Provider:          event.Provider,
+			PromptFingerprint: event.PromptFingerprint,
+			Error:             event.Error,
+		})
+		if len(s.errors) > s.maxErrors {
+			s.errors = s.errors[len(s.errors)-s.maxErrors:]
+		}
+	}
+	return nil
+}
+
+// Usage returns the aggregated usage of every provider, sorted by name.
+func (s *Stats) Usage() []ProviderUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usage := make([]ProviderUsage, 0, len(s.usage))
+	for _, u := range s.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Provider < usage[j].Provider
+	})
+	return usage
+}
+
+// RecentErrors returns the most recent errors, newest first.
+func (s *Stats) RecentErrors() []ErrorRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	errors := make([]ErrorRecord, len(s.errors))
+	for i, record := range s.errors {
+		errors[len(s.errors)-1-i] = record
+	}
+	return errors
+}
@@ -0,0 +1,193 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/textdiff"
+	"github.com/bpradana/tars/retriever"
+	"github.com/bpradana/tars/template"
+)
+
+// Candidate is a provider/model combination taking part in a comparison.
+type Candidate struct {
+	// Name identifies the candidate in reports (e.g. "gpt-4o-mini").
+	Name string
+
+	// Provider serves the candidate's requests.
+	Provider llm.BaseProvider
+
+	// Options are applied to every invocation of the candidate, typically WithModel.
+	Options []llm.InvokeOption
+}
+
+// Outcome is the response of a single candidate.
+type Outcome struct {
+	Candidate string
+	Content   string
+	Latency   time.Duration
+	Err       error
+}
+
+// Verdict is the judgement of the judge provider on two outputs.
+type Verdict struct {
+	Equivalent bool   `json:"equivalent" jsonschema:"description=Whether both answers convey the same information"`
+	Preferred  string `json:"preferred" jsonschema:"enum=a,enum=b,enum=tie,description=Which answer is better"`
+	Reason     string `json:"reason" jsonschema:"description=Short justification of the verdict"`
+}
+
+// Report is the structured comparison of two candidates on the same template.
+type Report struct {
+	A Outcome
+	B Outcome
+
+	// Diff is the line diff from A's output to B's output.
+	Diff []textdiff.Line
+
+	// Changed is true when the outputs differ textually.
+	Changed bool
+
+	// Distance is the cosine distance between the output embeddings.
+	// It is nil unless an embedder was configured.
+	Distance *float64
+
+	// Verdict is the judge's verdict. It is nil unless a judge was configured.
+	Verdict *Verdict
+}
+
+// options contains configuration options for a comparison.
+type options struct {
+	embedder     retriever.Embedder
+	judge        llm.BaseProvider
+	judgeOptions []llm.InvokeOption
+}
+
+// Option is a function type that modifies comparison options.
+type Option func(*options)
+
+// WithEmbedder enables the embedding distance between the two outputs.
+//
+// Example:
+//
+//	report, err := Run(ctx, tmpl, current, next, WithEmbedder(embedder))
+func WithEmbedder(embedder retriever.Embedder) Option {
+	return func(o *options) {
+		o.embedder = embedder
+	}
+}
+
+// WithJudge enables an LLM verdict on the two outputs using the given provider.
+//
+// Example:
+//
+//	report, err := Run(ctx, tmpl, current, next,
+//	  WithJudge(judge, llm.WithModel("gpt-4o"), llm.WithTemperature(0)),
+//	)
+func WithJudge(judge llm.BaseProvider, invokeOptions ...llm.InvokeOption) Option {
+	return func(o *options) {
+		o.judge = judge
+		o.judgeOptions = invokeOptions
+	}
+}
+
+// Run sends the same template to both candidates and reports how their
+// outputs differ. Candidate errors are reported in the outcomes; Run only
+// fails when the embedder or the judge fails.
+//
+// Example:
+//
+//	report, err := Run(ctx, tmpl,
+//	  Candidate{Name: "current", Provider: openai, Options: []llm.InvokeOption{llm.WithModel("gpt-4o")}},
+//	  Candidate{Name: "next", Provider: openai, Options: []llm.InvokeOption{llm.WithModel("gpt-4.1")}},
+//	)
+//	fmt.Print(textdiff.String(report.Diff))
+func Run(ctx context.Context, tmpl template.Template, a, b Candidate, opts ...Option) (Report, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var report Report
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		report.A = invoke(ctx, tmpl, a)
+	}()
+	go func() {
+		defer wg.Done()
+		report.B = invoke(ctx, tmpl, b)
+	}()
+	wg.Wait()
+
+	report.Diff = textdiff.Lines(report.A.Content, report.B.Content)
+	report.Changed = textdiff.Changed(report.Diff)
+
+	if report.A.Err != nil || report.B.Err != nil {
+		return report, nil
+	}
+
+	if o.embedder != nil {
+		embeddings, err := o.embedder.Embed(ctx, []string{report.A.Content, report.B.Content})
+		if err != nil {
+			return report, errorbank.NewMessageError("embed", "failed to embed outputs", err)
+		}
+		if len(embeddings) != 2 {
+			return report, errorbank.NewMessageError("embed", "embedder returned an unexpected number of vectors", nil)
+		}
+		distance := 1 - retriever.Cosine(embeddings[0], embeddings[1])
+		report.Distance = &distance
+	}
+
+	if o.judge != nil {
+		verdict, err := judge(ctx, o, tmpl, report)
+		if err != nil {
+			return report, err
+		}
+		report.Verdict = verdict
+	}
+
+	return report, nil
+}
+
+// invoke runs a single candidate and records its outcome.
+func invoke(ctx context.Context, tmpl template.Template, candidate Candidate) Outcome {
+	start := time.Now()
+	response, err := candidate.Provider.Invoke(ctx, tmpl, candidate.Options...)
+
+	outcome := Outcome{
+		Candidate: candidate.Name,
+		Latency:   time.Since(start),
+		Err:       err,
+	}
+	if err == nil {
+		outcome.Content = response.GetContent()
+	}
+	return outcome
+}
+
+// judge asks the judge provider to compare both outputs.
+func judge(ctx context.Context, o options, tmpl template.Template, report Report) (*Verdict, error) {
+	prompt := fmt.Sprintf(
+		"Conversation:\n%s\n\nAnswer A:\n%s\n\nAnswer B:\n%s",
+		tmpl.ToJSON(), report.A.Content, report.B.Content,
+	)
+
+	var verdict Verdict
+	_, err := o.judge.Invoke(ctx,
+		template.From(
+			message.FromSystem("You compare two answers to the same conversation. Decide whether they are equivalent and which one is better."),
+			message.FromUser(prompt),
+		),
+		append(o.judgeOptions, llm.WithStructuredOutput(&verdict))...,
+	)
+	if err != nil {
+		return nil, errorbank.NewMessageError("judge", "failed to obtain verdict", err)
+	}
+	return &verdict, nil
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// App is a ready-to-use tars runtime built from a Config.
+// It owns the configured providers and templates, and resolves model
+// aliases into a provider plus the invoke options declared for the alias.
+//
+// The state of an App is swapped atomically on reload, so it is safe to
+// use from multiple goroutines while a Watch is running.
+type App struct {
+	path  string
+	state atomic.Pointer[appState]
+}
+
+// appState is an immutable snapshot of everything built from a Config.
+type appState struct {
+	config    Config
+	providers map[string]llm.BaseProvider
+	templates map[string]template.Template
+}
+
+// Load reads the configuration file at the given path and builds an App from it.
+//
+// Example:
+//
+//	app, err := config.Load("tars.yaml")
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	response, err := app.Invoke(ctx, "fast", "greeting", map[string]any{"Name": "Alice"})
+func Load(path string) (*App, error) {
+	cfg, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.path = path
+	return app, nil
+}
+
+// New builds an App from an already parsed configuration.
+func New(cfg Config) (*App, error) {
+	state, err := newAppState(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &App{}
+	app.state.Store(state)
+	return app, nil
+}
+
+// newAppState builds the providers and templates declared in a configuration.
+func newAppState(cfg Config) (*appState, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]llm.BaseProvider, len(cfg.Providers))
+	for name, providerConfig := range cfg.Providers {
+		provider, err := newProvider(name, providerConfig)
+		if err != nil {
+			return nil, err
+		}
+		providers[name] = provider
+	}
+
+	templates := make(map[string]template.Template, len(cfg.Templates))
+	for name, templateConfig := range cfg.Templates {
+		tmpl, err := newTemplate(name, templateConfig, cfg.Env)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = tmpl
+	}
+
+	return &appState{
+		config:    cfg,
+		providers: providers,
+		templates: templates,
+	}, nil
+}
+
+// Config returns the configuration the App was built from.
+func (a *App) Config() Config {
+	return a.state.Load().config
+}
+
+// Provider returns the provider registered under the given name.
+func (a *App) Provider(name string) (llm.BaseProvider, error) {
+	provider, ok := a.state.Load().providers[name]
+	if !ok {
+		return nil, errorbank.NewValidationError("provider", "unknown provider", name)
+	}
+	return provider, nil
+}
+
+// Providers returns the configured providers, keyed by name.
+func (a *App) Providers() map[string]llm.BaseProvider {
+	state := a.state.Load()
+	providers := make(map[string]llm.BaseProvider, len(state.providers))
+	for name, provider := range state.providers {
+		providers[name] = provider
+	}
+	return providers
+}
+
+// Template returns the named template.
+func (a *App) Template(name string) (template.Template, error) {
+	tmpl, ok := a.state.Load().templates[name]
+	if !ok {
+		return nil, errorbank.NewValidationError("template", "unknown template", name)
+	}
+	return tmpl, nil
+}
+
+// Model resolves a model alias into its provider and the invoke options
+// configured for it.
+func (a *App) Model(alias string) (llm.BaseProvider, []llm.InvokeOption, error) {
+	state := a.state.Load()
+
+	model, ok := state.config.Models[alias]
+	if !ok {
+		return nil, nil, errorbank.NewValidationError("model", "unknown model alias", alias)
+	}
+
+	provider, ok := state.providers[model.Provider]
+	if !ok {
+		return nil, nil, errorbank.NewValidationError("provider", "unknown provider", model.Provider)
+	}
+
+	options := []llm.InvokeOption{llm.WithModel(model.Model)}
+	if model.Temperature != nil {
+		options = append(options, llm.WithTemperature(*model.Temperature))
+	}
+	if model.MaxTokens > 0 {
+		options = append(options, llm.WithMaxTokens(model.MaxTokens))
+	}
+
+	return provider, options, nil
+}
+
+// Invoke renders the named template with the given variables and sends it
+// to the provider behind the model alias. Additional options are applied
+// after the alias defaults, so they take precedence.
+func (a *App) Invoke(ctx context.Context, alias string, templateName string, v any, options ...llm.InvokeOption) (message.Message, error) {
+	provider, modelOptions, err := a.Model(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := a.Template(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Invoke(ctx, tmpl.Invoke(v), append(modelOptions, options...)...)
+}
+
+// newProvider constructs a provider from its configuration.
+func newProvider(name string, cfg ProviderConfig) (llm.BaseProvider, error) {
+	providerType := cfg.Type
+	if providerType == "" {
+		providerType = name
+	}
+
+	var options []llm.LLMOption
+	if cfg.BaseURL != "" {
+		options = append(options, llm.WithBaseURL(cfg.BaseURL))
+	}
+
+	apiKey := cfg.APIKey
+	if cfg.APIKeyEnv != "" {
+		apiKey = os.Getenv(cfg.APIKeyEnv)
+	}
+	if apiKey != "" {
+		options = append(options, llm.WithAPIKey(apiKey))
+	}
+
+	if cfg.Timeout > 0 {
+		options = append(options, llm.WithTimeout(cfg.Timeout))
+	}
+	if cfg.Retry.MaxAttempts > 0 {
+		options = append(options, llm.WithMaxAttempts(cfg.Retry.MaxAttempts))
+	}
+	if cfg.Retry.Delay > 0 {
+		options = append(options, llm.WithMaxDelay(cfg.Retry.Delay))
+	}
+
+	provider, err := llm.NewProvider(llm.ProviderType(providerType), options...)
+	if err != nil {
+		return nil, errorbank.NewValidationError(fmt.Sprintf("providers.%s.type", name), err.Error(), providerType)
+	}
+	return provider, nil
+}
+
+// newTemplate constructs a template from its configuration, interpolating
+// allowed environment variables into message contents.
+func newTemplate(name string, cfg TemplateConfig, env EnvConfig) (template.Template, error) {
+	messages := make([]message.Message, len(cfg.Messages))
+	for i, msg := range cfg.Messages {
+		content, err := env.Expand(msg.Content)
+		if err != nil {
+			return nil, errorbank.NewTemplateError(fmt.Sprintf("templates.%s.messages[%d].content", name, i), "failed to interpolate environment variables", err)
+		}
+
+		switch message.RoleType(msg.Role) {
+		case message.RoleSystem:
+			messages[i] = message.FromSystem(content)
+		case message.RoleUser:
+			messages[i] = message.FromUser(content)
+		case message.RoleAssistant:
+			messages[i] = message.FromAssistant(content)
+		default:
+			return nil, errorbank.NewValidationError(fmt.Sprintf("templates.%s.messages[%d].role", name, i), "invalid role type", msg.Role)
+		}
+	}
+
+	tmpl := template.From(messages...)
+	if err := tmpl.Validate(); err != nil {
+		return nil, errorbank.NewTemplateError(name, "invalid template", err)
+	}
+	return tmpl, nil
+}
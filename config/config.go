@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a complete tars application: the providers it talks to,
+// the model aliases used by application code, and the named templates.
+//
+// Example:
+//
+//	providers:
+//	  openai:
+//	    type: openai
+//	    api_key_env: OPENAI_API_KEY
+//	    timeout: 30s
+//	    retry:
+//	      max_attempts: 3
+//	      delay: 2s
+//	models:
+//	  fast:
+//	    provider: openai
+//	    model: gpt-4o-mini
+//	    temperature: 0.2
+//	templates:
+//	  greeting:
+//	    messages:
+//	      - role: system
+//	        content: You are a friendly assistant.
+//	      - role: user
+//	        content: Say hello to {{.Name}}.
+//	    variables:
+//	      Name:
+//	        description: The name of the person to greet.
+//	        example: Alice
+type Config struct {
+	Providers map[string]ProviderConfig `yaml:"providers"`
+	Models    map[string]ModelConfig    `yaml:"models"`
+	Templates map[string]TemplateConfig `yaml:"templates"`
+	Env       EnvConfig                 `yaml:"env"`
+}
+
+// ProviderConfig contains the settings used to construct a single provider.
+type ProviderConfig struct {
+	// Type is the provider type (openai, anthropic, openrouter, ollama).
+	// It defaults to the provider's name in the providers map.
+	Type string `yaml:"type"`
+
+	// BaseURL overrides the provider's default API endpoint.
+	BaseURL string `yaml:"base_url"`
+
+	// APIKey is the literal API key. Prefer APIKeyEnv to keep secrets out of files.
+	APIKey string `yaml:"api_key"`
+
+	// APIKeyEnv is the name of the environment variable holding the API key.
+	APIKeyEnv string `yaml:"api_key_env"`
+
+	// Timeout is the HTTP timeout for requests (e.g. "30s").
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Retry configures the retry policy of the provider.
+	Retry RetryConfig `yaml:"retry"`
+}
+
+// RetryConfig contains the retry policy of a provider.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	Delay       time.Duration `yaml:"delay"`
+}
+
+// ModelConfig maps an application-level model alias to a provider and model,
+// along with the default invoke options used for that alias.
+type ModelConfig struct {
+	Provider    string   `yaml:"provider"`
+	Model       string   `yaml:"model"`
+	Temperature *float64 `yaml:"temperature"`
+	MaxTokens   int      `yaml:"max_tokens"`
+}
+
+// TemplateConfig describes a named conversation template.
+type TemplateConfig struct {
+	// Description explains what the template is for.
+	Description string `yaml:"description"`
+
+	Messages []MessageConfig `yaml:"messages"`
+
+	// Variables documents the variables of the template, keyed by name.
+	// Undocumented variables are still listed in the manifest with the
+	// type inferred from the template content.
+	Variables map[string]VariableConfig `yaml:"variables"`
+}
+
+// VariableConfig documents a single template variable.
+type VariableConfig struct {
+	// Type overrides the type inferred from the template content
+	// (string, boolean, object, array, number).
+	Type string `yaml:"type"`
+
+	Description string `yaml:"description"`
+	Example     any    `yaml:"example"`
+}
+
+// MessageConfig describes a single message of a template.
+type MessageConfig struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content"`
+}
+
+// Parse decodes a YAML configuration document.
+// JSON documents are accepted as well since JSON is a subset of YAML.
+func Parse(data []byte) (Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, errorbank.NewMessageError("config_parse", "failed to parse configuration", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// ReadFile reads and parses the configuration file at the given path.
+func ReadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, errorbank.NewMessageError("config_read", "failed to read configuration file", err)
+	}
+	return Parse(data)
+}
+
+// Validate checks that every model alias references a declared provider,
+// that every template message has a role and content, and that environment
+// defaults only cover allowed variables.
+func (c Config) Validate() error {
+	if err := c.Env.validate(); err != nil {
+		return err
+	}
+
+	for alias, model := range c.Models {
+		if _, ok := c.Providers[model.Provider]; !ok {
+			return errorbank.NewValidationError(fmt.Sprintf("models.%s.provider", alias), "unknown provider", model.Provider)
+		}
+		if model.Model == "" {
+			return errorbank.NewValidationError(fmt.Sprintf("models.%s.model", alias), "cannot be empty", model.Model)
+		}
+	}
+
+	for name, tmpl := range c.Templates {
+		if len(tmpl.Messages) == 0 {
+			return errorbank.NewValidationError(fmt.Sprintf("templates.%s.messages", name), "template cannot be empty", tmpl.Messages)
+		}
+		for i, msg := range tmpl.Messages {
+			if msg.Role == "" {
+				return errorbank.NewValidationError(fmt.Sprintf("templates.%s.messages[%d].role", name, i), "cannot be empty", msg.Role)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// EnvConfig controls ${ENV_VAR} interpolation in template messages, which
+// lets prompts reference deployment-specific values such as a product name
+// or support URL without keeping a file per environment.
+//
+// Interpolation is disabled unless at least one variable is allowed, so
+// that templates cannot read secrets such as API keys from the environment.
+// A reference may carry an inline default, ${NAME:-default}, and a literal
+// "${" is written as "$${".
+//
+// Example:
+//
+//	env:
+//	  allow: [PRODUCT_NAME, SUPPORT_URL]
+//	  defaults:
+//	    SUPPORT_URL: https://example.com/help
+//	templates:
+//	  support:
+//	    messages:
+//	      - role: system
+//	        content: You are the ${PRODUCT_NAME} assistant. Link to ${SUPPORT_URL} when unsure.
+type EnvConfig struct {
+	// Allow lists the environment variables templates may reference.
+	Allow []string `yaml:"allow"`
+
+	// Defaults holds values used when an allowed variable is unset.
+	Defaults map[string]string `yaml:"defaults"`
+}
+
+// envReference matches an escaped "$${" or a ${NAME} / ${NAME:-default} reference.
+var envReference = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// enabled reports whether interpolation is turned on.
+func (e EnvConfig) enabled() bool {
+	return len(e.Allow) > 0
+}
+
+// validate checks that every default belongs to an allowed variable.
+func (e EnvConfig) validate() error {
+	for name := range e.Defaults {
+		if !slices.Contains(e.Allow, name) {
+			return errorbank.NewValidationError(fmt.Sprintf("env.defaults.%s", name), "variable is not in the allow list", name)
+		}
+	}
+	return nil
+}
+
+// Expand replaces environment variable references in content. Referencing
+// a variable outside the allow list, or an unset variable without a
+// default, is an error.
+func (e EnvConfig) Expand(content string) (string, error) {
+	if !e.enabled() {
+		return content, nil
+	}
+
+	var err error
+	expanded := envReference.ReplaceAllStringFunc(content, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+
+		groups := envReference.FindStringSubmatch(match)
+		name, fallback := groups[1], groups[2]
+		hasFallback := len(match) > len(name)+3
+
+		if !slices.Contains(e.Allow, name) {
+			if err == nil {
+				err = errorbank.NewValidationError(name, "environment variable is not in the allow list", match)
+			}
+			return match
+		}
+
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if value, ok := e.Defaults[name]; ok {
+			return value
+		}
+		if hasFallback {
+			return fallback
+		}
+
+		if err == nil {
+			err = errorbank.NewValidationError(name, "environment variable is not set", match)
+		}
+		return match
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
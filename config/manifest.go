@@ -0,0 +1,99 @@
+package config
+
+import (
+	"sort"
+)
+
+// Manifest is a machine-readable description of the templates of an App,
+// for building prompt catalogs and admin UIs.
+type Manifest struct {
+	Templates []TemplateManifest `json:"templates"`
+}
+
+// TemplateManifest describes a single named template.
+type TemplateManifest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Fingerprint string             `json:"fingerprint"`
+	Variables   []VariableManifest `json:"variables"`
+}
+
+// VariableManifest describes a template variable. Used reports whether the
+// template content reads the variable, so variables that are documented but
+// no longer referenced can be spotted.
+type VariableManifest struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Example     any    `json:"example,omitempty"`
+	Used        bool   `json:"used"`
+}
+
+// Manifest describes every template of the App, sorted by name. Variables
+// are introspected from the template content and enriched with the
+// documentation declared in the configuration.
+//
+// Example:
+//
+//	data, err := json.MarshalIndent(app.Manifest(), "", "  ")
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	os.WriteFile("prompts.json", data, 0o644)
+func (a *App) Manifest() Manifest {
+	state := a.state.Load()
+
+	names := make([]string, 0, len(state.templates))
+	for name := range state.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := Manifest{Templates: make([]TemplateManifest, 0, len(names))}
+	for _, name := range names {
+		tmpl := state.templates[name]
+		cfg := state.config.Templates[name]
+
+		variables := []VariableManifest{}
+		seen := map[string]bool{}
+		for _, v := range tmpl.Variables() {
+			seen[v.Name] = true
+			variables = append(variables, newVariableManifest(v.Name, v.Type, true, cfg.Variables[v.Name]))
+		}
+
+		// Documented variables that the content does not read come last.
+		var unused []string
+		for name := range cfg.Variables {
+			if !seen[name] {
+				unused = append(unused, name)
+			}
+		}
+		sort.Strings(unused)
+		for _, name := range unused {
+			variables = append(variables, newVariableManifest(name, "string", false, cfg.Variables[name]))
+		}
+
+		manifest.Templates = append(manifest.Templates, TemplateManifest{
+			Name:        name,
+			Description: cfg.Description,
+			Fingerprint: tmpl.Fingerprint(),
+			Variables:   variables,
+		})
+	}
+	return manifest
+}
+
+// newVariableManifest combines an introspected variable with its documentation.
+func newVariableManifest(name, inferred string, used bool, cfg VariableConfig) VariableManifest {
+	typ := inferred
+	if cfg.Type != "" {
+		typ = cfg.Type
+	}
+	return VariableManifest{
+		Name:        name,
+		Type:        typ,
+		Description: cfg.Description,
+		Example:     cfg.Example,
+		Used:        used,
+	}
+}
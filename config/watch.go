@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// ReloadEvent describes the outcome of a configuration reload.
+type ReloadEvent struct {
+	// Path is the configuration file that was reloaded.
+	Path string
+
+	// Time is when the reload was attempted.
+	Time time.Time
+
+	// Err is set when the new configuration could not be applied.
+	// The App keeps serving the previous configuration in that case.
+	Err error
+}
+
+// ReloadHook is called after every reload attempt, successful or not.
+// Services typically use it to log or emit reload events.
+type ReloadHook func(event ReloadEvent)
+
+// Reload re-reads the configuration file the App was loaded from and
+// atomically swaps providers, model aliases, and templates.
+// If the new configuration is invalid, the current state is kept and an error is returned.
+func (a *App) Reload() error {
+	if a.path == "" {
+		return errorbank.NewValidationError("path", "app was not loaded from a file", a.path)
+	}
+
+	cfg, err := ReadFile(a.path)
+	if err != nil {
+		return err
+	}
+
+	state, err := newAppState(cfg)
+	if err != nil {
+		return err
+	}
+
+	a.state.Store(state)
+	return nil
+}
+
+// Watch polls the configuration file every interval and reloads the App
+// whenever the file changes. It blocks until the context is cancelled,
+// so it is usually started in its own goroutine.
+//
+// Only the configuration file is watched. Templates are declared in that
+// file and reload with it; there is no separate prompt directory to watch.
+//
+// Example:
+//
+//	go app.Watch(ctx, 5*time.Second, func(event config.ReloadEvent) {
+//	  if event.Err != nil {
+//	    log.Printf("config reload failed: %v", event.Err)
+//	    return
+//	  }
+//	  log.Printf("config reloaded from %s", event.Path)
+//	})
+func (a *App) Watch(ctx context.Context, interval time.Duration, hook ReloadHook) error {
+	if a.path == "" {
+		return errorbank.NewValidationError("path", "app was not loaded from a file", a.path)
+	}
+	if interval <= 0 {
+		return errorbank.NewValidationError("interval", "must be positive", interval)
+	}
+
+	last, err := os.Stat(a.path)
+	if err != nil {
+		return errorbank.NewMessageError("config_watch", "failed to stat configuration file", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(a.path)
+		if err != nil || (info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+			continue
+		}
+		last = info
+
+		event := ReloadEvent{
+			Path: a.path,
+			Time: time.Now(),
+			Err:  a.Reload(),
+		}
+		if hook != nil {
+			hook(event)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"time"
+)
+
+// Event is a single usage/transcript record shipped to an analytics sink.
+type Event struct {
+	// Time is when the invocation started.
+	Time time.Time `json:"time"`
+
+	// Provider is the name of the provider that served the request.
+	Provider string `json:"provider"`
+
+	// Prompt is the JSON-serialized template sent to the provider.
+	Prompt string `json:"prompt"`
+
+	// PromptFingerprint is the content hash of the prompt, see Template.Fingerprint.
+	PromptFingerprint string `json:"prompt_fingerprint"`
+
+	// Completion is the content of the response, empty on error.
+	Completion string `json:"completion,omitempty"`
+
+	// PromptTokens, CompletionTokens, and TotalTokens carry the reported usage.
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+
+	// Latency is the wall-clock duration of the invocation.
+	Latency time.Duration `json:"latency"`
+
+	// Error is the error message when the invocation failed.
+	Error string `json:"error,omitempty"`
+}
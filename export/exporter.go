@@ -0,0 +1,194 @@
+package export
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+const (
+	// defaultBatchSize is the number of events that triggers a flush by default.
+	defaultBatchSize = 100
+
+	// defaultFlushInterval is how often buffered events are shipped by default.
+	defaultFlushInterval = 10 * time.Second
+)
+
+// exporterOptions contains configuration options for an Exporter.
+type exporterOptions struct {
+	batchSize     int
+	flushInterval time.Duration
+	onError       func(error)
+}
+
+// ExporterOption is a function type that modifies exporter options.
+type ExporterOption func(*exporterOptions)
+
+// WithBatchSize sets the number of events that triggers an immediate flush.
+// The default is 100; values below 1 use the default.
+//
+// Example:
+//
+//	exporter := NewExporter(sink, WithBatchSize(500))
+func WithBatchSize(batchSize int) ExporterOption {
+	return func(e *exporterOptions) {
+		e.batchSize = batchSize
+	}
+}
+
+// WithFlushInterval sets how often buffered events are shipped to the sink.
+// The default is 10 seconds; non-positive intervals use the default.
+//
+// Example:
+//
+//	exporter := NewExporter(sink, WithFlushInterval(time.Minute))
+func WithFlushInterval(flushInterval time.Duration) ExporterOption {
+	return func(e *exporterOptions) {
+		e.flushInterval = flushInterval
+	}
+}
+
+// WithErrorHandler sets a callback for sink errors.
+// Events of a failed batch are dropped after the handler is called, as are
+// events recorded after the exporter was closed.
+//
+// Example:
+//
+//	exporter := NewExporter(sink, WithErrorHandler(func(err error) {
+//	  log.Printf("export failed: %v", err)
+//	}))
+func WithErrorHandler(onError func(error)) ExporterOption {
+	return func(e *exporterOptions) {
+		e.onError = onError
+	}
+}
+
+// Exporter buffers events and ships them to a Sink in batches,
+// either when the batch is full or on every flush interval.
+type Exporter struct {
+	sink    Sink
+	options exporterOptions
+
+	mu     sync.Mutex
+	buffer []Event
+	closed bool
+
+	flush chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewExporter creates an exporter and starts its background flush loop.
+// Close must be called to flush remaining events and stop the loop.
+//
+// Example:
+//
+//	exporter := NewExporter(NewJSONLSink("usage.jsonl"),
+//	  WithFlushInterval(30*time.Second),
+//	)
+//	defer exporter.Close()
+func NewExporter(sink Sink, options ...ExporterOption) *Exporter {
+	opts := exporterOptions{
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	// Invalid settings fall back to the defaults instead of panicking in
+	// the background flush loop.
+	if opts.batchSize <= 0 {
+		opts.batchSize = defaultBatchSize
+	}
+	if opts.flushInterval <= 0 {
+		opts.flushInterval = defaultFlushInterval
+	}
+
+	e := &Exporter{
+		sink:    sink,
+		options: opts,
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	e.wg.Add(1)
+	go e.run()
+
+	return e
+}
+
+// Record adds an event to the buffer. Events recorded after Close are
+// dropped, since nothing would ship them, and reported to the error handler.
+func (e *Exporter) Record(event Event) {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		if e.options.onError != nil {
+			e.options.onError(errorbank.NewMessageError("export_closed", "event recorded after the exporter was closed", nil))
+		}
+		return
+	}
+	e.buffer = append(e.buffer, event)
+	full := len(e.buffer) >= e.options.batchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close flushes any buffered events and stops the exporter.
+// Events recorded after Close are dropped.
+func (e *Exporter) Close() {
+	e.once.Do(func() {
+		e.mu.Lock()
+		e.closed = true
+		e.mu.Unlock()
+
+		close(e.done)
+		e.wg.Wait()
+	})
+}
+
+// run is the background loop that ships batches to the sink.
+func (e *Exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.options.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.ship()
+		case <-e.flush:
+			e.ship()
+		case <-e.done:
+			e.ship()
+			return
+		}
+	}
+}
+
+// ship sends the current buffer to the sink.
+func (e *Exporter) ship() {
+	e.mu.Lock()
+	batch := e.buffer
+	e.buffer = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := e.sink.Write(context.Background(), batch); err != nil && e.options.onError != nil {
+		e.options.onError(err)
+	}
+}
@@ -0,0 +1,193 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// LangfuseSink reports events to Langfuse through its batch ingestion API.
+// Each event becomes a trace with a single generation carrying the prompt,
+// completion, token usage, and error status.
+type LangfuseSink struct {
+	client *httpx.Client
+}
+
+// NewLangfuseSink creates a sink for the Langfuse instance at host
+// (e.g. "https://cloud.langfuse.com"), authenticated with the project keys.
+//
+// Example:
+//
+//	sink := NewLangfuseSink("https://cloud.langfuse.com",
+//	  os.Getenv("LANGFUSE_PUBLIC_KEY"),
+//	  os.Getenv("LANGFUSE_SECRET_KEY"),
+//	)
+func NewLangfuseSink(host, publicKey, secretKey string) *LangfuseSink {
+	credentials := base64.StdEncoding.EncodeToString([]byte(publicKey + ":" + secretKey))
+
+	return &LangfuseSink{
+		client: httpx.NewClient().
+			WithBaseURL(host).
+			WithDefaultHeaders(httpx.NewHeader().Authorization("Basic " + credentials)),
+	}
+}
+
+// langfuseEvent is a single entry of a Langfuse ingestion batch.
+type langfuseEvent struct {
+	ID        string         `json:"id"`
+	Timestamp string         `json:"timestamp"`
+	Type      string         `json:"type"`
+	Body      map[string]any `json:"body"`
+}
+
+// Write sends the events as one ingestion batch.
+func (s *LangfuseSink) Write(ctx context.Context, events []Event) error {
+	batch := make([]langfuseEvent, 0, len(events)*2)
+	for _, event := range events {
+		traceID := newID()
+		start := event.Time.UTC().Format(time.RFC3339Nano)
+		end := event.Time.Add(event.Latency).UTC().Format(time.RFC3339Nano)
+
+		batch = append(batch, langfuseEvent{
+			ID:        newID(),
+			Timestamp: start,
+			Type:      "trace-create",
+			Body: map[string]any{
+				"id":        traceID,
+				"name":      event.Provider,
+				"timestamp": start,
+				"input":     event.Prompt,
+				"output":    event.Completion,
+				"metadata":  map[string]string{"prompt_fingerprint": event.PromptFingerprint},
+			},
+		})
+
+		generation := map[string]any{
+			"id":        newID(),
+			"traceId":   traceID,
+			"name":      event.Provider,
+			"startTime": start,
+			"endTime":   end,
+			"input":     event.Prompt,
+			"output":    event.Completion,
+			"usage": map[string]int{
+				"input":  event.PromptTokens,
+				"output": event.CompletionTokens,
+				"total":  event.TotalTokens,
+			},
+		}
+		if event.Error != "" {
+			generation["level"] = "ERROR"
+			generation["statusMessage"] = event.Error
+		}
+
+		batch = append(batch, langfuseEvent{
+			ID:        newID(),
+			Timestamp: start,
+			Type:      "generation-create",
+			Body:      generation,
+		})
+	}
+
+	resp, err := s.client.Post("/api/public/ingestion", map[string]any{"batch": batch})
+	if err != nil {
+		return errorbank.NewMessageError("export_write", "failed to send events to Langfuse", err)
+	}
+	defer resp.Body.Close()
+
+	if err := resp.Error(); err != nil {
+		return errorbank.NewMessageError("export_write", "Langfuse rejected events", err)
+	}
+	return nil
+}
+
+// LangSmithSink reports events to LangSmith (or a compatible endpoint)
+// through its batch runs API. Each event becomes an "llm" run.
+type LangSmithSink struct {
+	client  *httpx.Client
+	project string
+}
+
+// NewLangSmithSink creates a sink for the LangSmith API at endpoint
+// (e.g. "https://api.smith.langchain.com"), recording runs under the given project.
+//
+// Example:
+//
+//	sink := NewLangSmithSink("https://api.smith.langchain.com",
+//	  os.Getenv("LANGSMITH_API_KEY"),
+//	  "my-service",
+//	)
+func NewLangSmithSink(endpoint, apiKey, project string) *LangSmithSink {
+	return &LangSmithSink{
+		client: httpx.NewClient().
+			WithBaseURL(endpoint).
+			WithDefaultHeader("x-api-key", apiKey),
+		project: project,
+	}
+}
+
+// Write sends the events as one batch of runs.
+func (s *LangSmithSink) Write(ctx context.Context, events []Event) error {
+	runs := make([]map[string]any, len(events))
+	for i, event := range events {
+		id := newUUID()
+		start := event.Time.UTC()
+		run := map[string]any{
+			"id":           id,
+			"trace_id":     id,
+			"dotted_order": fmt.Sprintf("%s%06dZ%s", start.Format("20060102T150405"), start.Nanosecond()/1000, id),
+			"name":         event.Provider,
+			"run_type":     "llm",
+			"session_name": s.project,
+			"start_time":   start.Format(time.RFC3339Nano),
+			"end_time":     start.Add(event.Latency).Format(time.RFC3339Nano),
+			"inputs":       map[string]any{"prompt": event.Prompt},
+			"extra":        map[string]any{"metadata": map[string]string{"prompt_fingerprint": event.PromptFingerprint}},
+			"outputs": map[string]any{
+				"completion": event.Completion,
+				"usage_metadata": map[string]int{
+					"input_tokens":  event.PromptTokens,
+					"output_tokens": event.CompletionTokens,
+					"total_tokens":  event.TotalTokens,
+				},
+			},
+		}
+		if event.Error != "" {
+			run["error"] = event.Error
+		}
+		runs[i] = run
+	}
+
+	resp, err := s.client.Post("/runs/batch", map[string]any{"post": runs})
+	if err != nil {
+		return errorbank.NewMessageError("export_write", "failed to send events to LangSmith", err)
+	}
+	defer resp.Body.Close()
+
+	if err := resp.Error(); err != nil {
+		return errorbank.NewMessageError("export_write", "LangSmith rejected events", err)
+	}
+	return nil
+}
+
+// newID returns a random hex identifier.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/template"
+)
+
+// provider wraps a BaseProvider and records every invocation as an Event.
+type provider struct {
+	llm.BaseProvider
+	exporter *Exporter
+}
+
+// Wrap returns a provider that records every invocation of the given
+// provider to the exporter, without changing its behavior.
+//
+// Example:
+//
+//	exporter := NewExporter(NewHTTPSink("https://analytics.example.com/events", nil))
+//	defer exporter.Close()
+//	provider := Wrap(llm.NewOpenAI(llm.WithAPIKey(key)), exporter)
+func Wrap(base llm.BaseProvider, exporter *Exporter) llm.BaseProvider {
+	return &provider{
+		BaseProvider: base,
+		exporter:     exporter,
+	}
+}
+
+// Unwrap returns the wrapped provider.
+func (p *provider) Unwrap() llm.BaseProvider {
+	return p.BaseProvider
+}
+
+// Invoke calls the wrapped provider and records the outcome.
+func (p *provider) Invoke(ctx context.Context, template template.Template, options ...llm.InvokeOption) (message.Message, error) {
+	start := time.Now()
+	response, err := p.BaseProvider.Invoke(ctx, template, options...)
+
+	event := Event{
+		Time:              start,
+		Provider:          p.GetName(),
+		Prompt:            template.ToJSON(),
+		PromptFingerprint: template.Fingerprint(),
+		Latency:           time.Since(start),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		usage := response.GetUsage()
+		event.Completion = response.GetContent()
+		event.PromptTokens = usage.PromptTokens
+		event.CompletionTokens = usage.CompletionTokens
+		event.TotalTokens = usage.TotalTokens
+	}
+
+	p.exporter.Record(event)
+	return response, err
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Sink receives batches of events from an Exporter.
+// Implementations ship events to their destination (files, HTTP endpoints,
+// object stores) and must be safe to call from the exporter goroutine.
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+}
+
+// JSONLSink appends events as JSON lines to a local file.
+type JSONLSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONLSink creates a sink that appends events to the file at path.
+// The file is created if it does not exist.
+func NewJSONLSink(path string) *JSONLSink {
+	return &JSONLSink{path: path}
+}
+
+// Write appends the events to the file, one JSON object per line.
+func (s *JSONLSink) Write(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errorbank.NewMessageError("export_write", "failed to open export file", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return errorbank.NewMessageError("export_write", "failed to write event", err)
+		}
+	}
+	return nil
+}
+
+// HTTPSink posts each batch of events as a JSON array to an HTTP endpoint.
+type HTTPSink struct {
+	client *httpx.Client
+	url    string
+}
+
+// NewHTTPSink creates a sink that posts batches to the given URL.
+// Headers such as authorization tokens can be supplied through the header argument.
+func NewHTTPSink(url string, header *httpx.Header) *HTTPSink {
+	client := httpx.NewClient()
+	if header != nil {
+		client.WithDefaultHeaders(header)
+	}
+
+	return &HTTPSink{
+		client: client,
+		url:    url,
+	}
+}
+
+// Write posts the events to the endpoint. The request is canceled when ctx is done.
+func (s *HTTPSink) Write(ctx context.Context, events []Event) error {
+	req, err := s.client.POST(s.url)
+	if err != nil {
+		return errorbank.NewMessageError("export_write", "failed to create request", err)
+	}
+	req.Request = req.Request.WithContext(ctx)
+
+	resp, err := req.WithJSON(events).Do()
+	if err != nil {
+		return errorbank.NewMessageError("export_write", "failed to post events", err)
+	}
+	defer resp.Body.Close()
+
+	if err := resp.Error(); err != nil {
+		return errorbank.NewMessageError("export_write", "export endpoint rejected events", err)
+	}
+	return nil
+}
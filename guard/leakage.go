@@ -0,0 +1,335 @@
+// Package guard checks prompts and model responses before they reach the
+// provider or the caller.
+package guard
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/retriever"
+	"github.com/bpradana/tars/template"
+)
+
+// Action is what a guard does with a response that leaks the system prompt.
+type Action int
+
+const (
+	// ActionBlock fails the invocation with a prompt_leakage error.
+	ActionBlock Action = iota
+
+	// ActionRedact replaces the leaked spans of the response.
+	ActionRedact
+)
+
+// leakageOptions contains configuration options for leakage detection.
+type leakageOptions struct {
+	minMatchWords       int
+	embedder            retriever.Embedder
+	similarityThreshold float64
+	action              Action
+	redaction           string
+}
+
+// LeakageOption is a function type that modifies leakage detection options.
+type LeakageOption func(*leakageOptions)
+
+// WithMinMatchWords sets how many consecutive words of the system prompt a
+// response must repeat to count as leakage. Words are compared case- and
+// punctuation-insensitively, so light reformatting is still detected.
+// The default is 12; lower values catch shorter leaks at the cost of
+// flagging common phrases. System prompts shorter than this are flagged
+// when the response repeats them in full.
+func WithMinMatchWords(words int) LeakageOption {
+	return func(o *leakageOptions) {
+		o.minMatchWords = words
+	}
+}
+
+// WithEmbeddingSimilarity also compares every paragraph of the response with
+// the system prompt by embedding, flagging paraphrased leaks whose cosine
+// similarity reaches the threshold (e.g. 0.9).
+//
+// Example:
+//
+//	provider := guard.NewLeakageGuard(base, guard.WithEmbeddingSimilarity(embedder, 0.9))
+func WithEmbeddingSimilarity(embedder retriever.Embedder, threshold float64) LeakageOption {
+	return func(o *leakageOptions) {
+		o.embedder = embedder
+		o.similarityThreshold = threshold
+	}
+}
+
+// WithAction sets whether leaking responses are blocked or redacted.
+// Responses are blocked by default.
+func WithAction(action Action) LeakageOption {
+	return func(o *leakageOptions) {
+		o.action = action
+	}
+}
+
+// WithRedaction sets the text that replaces leaked spans. The default is "[REDACTED]".
+func WithRedaction(redaction string) LeakageOption {
+	return func(o *leakageOptions) {
+		o.redaction = redaction
+	}
+}
+
+// newLeakageOptions applies the options over the defaults.
+func newLeakageOptions(options []LeakageOption) leakageOptions {
+	opts := leakageOptions{
+		minMatchWords: 12,
+		action:        ActionBlock,
+		redaction:     "[REDACTED]",
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// Span is a byte range of a response.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Leakage is the outcome of comparing a response with a system prompt.
+type Leakage struct {
+	// Detected is true when any leakage check fired.
+	Detected bool
+
+	// Overlap is the fraction of the system prompt's word sequences that
+	// appear verbatim in the response.
+	Overlap float64
+
+	// Similarity is the highest embedding similarity between a paragraph of
+	// the response and the system prompt, when embeddings are enabled.
+	Similarity float64
+
+	// Spans are the leaked ranges of the response, sorted and non-overlapping.
+	Spans []Span
+}
+
+// DetectLeakage checks whether the response repeats the system prompt.
+//
+// Example:
+//
+//	leakage, err := guard.DetectLeakage(ctx, systemPrompt, response.GetContent())
+//	if leakage.Detected {
+//	  log.Printf("system prompt leaked: %.0f%% overlap", leakage.Overlap*100)
+//	}
+func DetectLeakage(ctx context.Context, systemPrompt, response string, options ...LeakageOption) (Leakage, error) {
+	return detectLeakage(ctx, systemPrompt, response, newLeakageOptions(options))
+}
+
+// detectLeakage implements DetectLeakage with resolved options.
+func detectLeakage(ctx context.Context, systemPrompt, response string, opts leakageOptions) (Leakage, error) {
+	var leakage Leakage
+	if strings.TrimSpace(systemPrompt) == "" || strings.TrimSpace(response) == "" {
+		return leakage, nil
+	}
+
+	promptWords := words(systemPrompt)
+	responseWords := words(response)
+
+	// A prompt shorter than the match window is leaked when the response
+	// repeats all of it, so the window never exceeds the prompt.
+	n := min(max(opts.minMatchWords, 1), len(promptWords))
+
+	shingles := make(map[string]bool)
+	for i := 0; n > 0 && i+n <= len(promptWords); i++ {
+		shingles[shingle(promptWords[i:i+n])] = true
+	}
+
+	matched := make(map[string]bool)
+	for i := 0; n > 0 && i+n <= len(responseWords); i++ {
+		key := shingle(responseWords[i : i+n])
+		if !shingles[key] {
+			continue
+		}
+		matched[key] = true
+		leakage.Spans = append(leakage.Spans, Span{
+			Start: responseWords[i].start,
+			End:   responseWords[i+n-1].end,
+		})
+	}
+	if len(shingles) > 0 {
+		leakage.Overlap = float64(len(matched)) / float64(len(shingles))
+	}
+
+	if opts.embedder != nil {
+		paragraphs := paragraphs(response)
+		texts := make([]string, 0, len(paragraphs)+1)
+		texts = append(texts, systemPrompt)
+		for _, paragraph := range paragraphs {
+			texts = append(texts, response[paragraph.Start:paragraph.End])
+		}
+
+		embeddings, err := opts.embedder.Embed(ctx, texts)
+		if err != nil {
+			return leakage, errorbank.NewMessageError("embed", "failed to embed response", err)
+		}
+		if len(embeddings) != len(texts) {
+			return leakage, errorbank.NewMessageError("embed", "embedder returned an unexpected number of vectors", nil)
+		}
+
+		for i, paragraph := range paragraphs {
+			similarity := retriever.Cosine(embeddings[0], embeddings[i+1])
+			leakage.Similarity = max(leakage.Similarity, similarity)
+			if similarity >= opts.similarityThreshold {
+				leakage.Spans = append(leakage.Spans, paragraph)
+			}
+		}
+	}
+
+	leakage.Spans = mergeSpans(leakage.Spans)
+	leakage.Detected = len(leakage.Spans) > 0
+	return leakage, nil
+}
+
+// Redact replaces the leaked spans of the response with the redaction text.
+func (l Leakage) Redact(response, redaction string) string {
+	var redacted strings.Builder
+	last := 0
+	for _, span := range l.Spans {
+		redacted.WriteString(response[last:span.Start])
+		redacted.WriteString(redaction)
+		last = span.End
+	}
+	redacted.WriteString(response[last:])
+	return redacted.String()
+}
+
+// leakageGuard wraps a provider and checks every response for leakage.
+type leakageGuard struct {
+	llm.BaseProvider
+	options leakageOptions
+}
+
+// NewLeakageGuard returns a provider that checks every response of base for
+// verbatim or near-verbatim leakage of the template's system messages, and
+// blocks or redacts leaking responses.
+//
+// Example:
+//
+//	provider := guard.NewLeakageGuard(llm.NewOpenAI(llm.WithAPIKey(key)),
+//	  guard.WithAction(guard.ActionRedact),
+//	)
+func NewLeakageGuard(base llm.BaseProvider, options ...LeakageOption) llm.BaseProvider {
+	return &leakageGuard{
+		BaseProvider: base,
+		options:      newLeakageOptions(options),
+	}
+}
+
+// Unwrap returns the wrapped provider.
+func (g *leakageGuard) Unwrap() llm.BaseProvider {
+	return g.BaseProvider
+}
+
+// Invoke calls the wrapped provider and checks the response.
+func (g *leakageGuard) Invoke(ctx context.Context, template template.Template, options ...llm.InvokeOption) (message.Message, error) {
+	response, err := g.BaseProvider.Invoke(ctx, template, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var system []string
+	for _, msg := range template.GetMessage() {
+		if msg.GetRole() == message.RoleSystem {
+			system = append(system, msg.GetContent())
+		}
+	}
+
+	leakage, err := detectLeakage(ctx, strings.Join(system, "\n\n"), response.GetContent(), g.options)
+	if err != nil {
+		return nil, err
+	}
+	if !leakage.Detected {
+		return response, nil
+	}
+
+	if g.options.action == ActionRedact {
+		return message.CopyWithContent(response, leakage.Redact(response.GetContent(), g.options.redaction)), nil
+	}
+	return nil, errorbank.NewMessageError("prompt_leakage", "response leaks the system prompt", nil)
+}
+
+// word is a normalized word with its byte range in the source text.
+type word struct {
+	text  string
+	start int
+	end   int
+}
+
+// wordPattern matches runs of letters and digits.
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// words splits text into lowercase words.
+func words(text string) []word {
+	matches := wordPattern.FindAllStringIndex(text, -1)
+	result := make([]word, len(matches))
+	for i, match := range matches {
+		result[i] = word{
+			text:  strings.ToLower(text[match[0]:match[1]]),
+			start: match[0],
+			end:   match[1],
+		}
+	}
+	return result
+}
+
+// shingle joins a sequence of words into a lookup key.
+func shingle(sequence []word) string {
+	texts := make([]string, len(sequence))
+	for i, w := range sequence {
+		texts[i] = w.text
+	}
+	return strings.Join(texts, " ")
+}
+
+// paragraphs returns the byte ranges of the non-blank paragraphs of text.
+func paragraphs(text string) []Span {
+	var spans []Span
+	start := 0
+	for start < len(text) {
+		end := strings.Index(text[start:], "\n\n")
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += start
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			spans = append(spans, Span{Start: start, End: end})
+		}
+		start = end + 2
+	}
+	return spans
+}
+
+// mergeSpans sorts spans and merges the ones that overlap or touch.
+func mergeSpans(spans []Span) []Span {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+
+	merged := []Span{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			last.End = max(last.End, span.End)
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
@@ -0,0 +1,124 @@
+package guard
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// moderationOptions contains configuration options for a moderation guard.
+type moderationOptions struct {
+	input  bool
+	output bool
+}
+
+// ModerationOption is a function type that modifies moderation guard options.
+type ModerationOption func(*moderationOptions)
+
+// WithInputModeration sets whether the user messages of the template are
+// moderated before the provider is invoked. Enabled by default.
+func WithInputModeration(enabled bool) ModerationOption {
+	return func(o *moderationOptions) {
+		o.input = enabled
+	}
+}
+
+// WithOutputModeration sets whether responses are moderated before they are
+// returned. Enabled by default.
+func WithOutputModeration(enabled bool) ModerationOption {
+	return func(o *moderationOptions) {
+		o.output = enabled
+	}
+}
+
+// moderationGuard wraps a provider and moderates its inputs and outputs.
+type moderationGuard struct {
+	llm.BaseProvider
+	moderator llm.Moderator
+	options   moderationOptions
+}
+
+// NewModerationGuard returns a provider that checks the user messages of
+// every template and every response of base with the moderator. Flagged
+// content is rejected with an *errorbank.ModerationError whose Stage is
+// "input" or "output"; flagged inputs never reach base.
+//
+// Example:
+//
+//	moderator := llm.NewOpenAI(llm.WithAPIKey(key)).(llm.Moderator)
+//	provider := guard.NewModerationGuard(llm.NewAnthropic(llm.WithAPIKey(anthropicKey)), moderator,
+//	  guard.WithOutputModeration(false),
+//	)
+//
+//	response, err := provider.Invoke(ctx, tmpl)
+//	var moderationErr *errorbank.ModerationError
+//	if errors.As(err, &moderationErr) {
+//	  log.Printf("%s rejected: %v", moderationErr.Stage, moderationErr.Categories)
+//	}
+func NewModerationGuard(base llm.BaseProvider, moderator llm.Moderator, options ...ModerationOption) llm.BaseProvider {
+	opts := moderationOptions{
+		input:  true,
+		output: true,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &moderationGuard{
+		BaseProvider: base,
+		moderator:    moderator,
+		options:      opts,
+	}
+}
+
+// Unwrap returns the wrapped provider.
+func (g *moderationGuard) Unwrap() llm.BaseProvider {
+	return g.BaseProvider
+}
+
+// Invoke moderates the input, calls the wrapped provider, and moderates the response.
+func (g *moderationGuard) Invoke(ctx context.Context, template template.Template, options ...llm.InvokeOption) (message.Message, error) {
+	if g.options.input {
+		var input []string
+		for _, msg := range template.GetMessage() {
+			if msg.GetRole() == message.RoleUser {
+				input = append(input, msg.GetContent())
+			}
+		}
+		if err := g.moderate(ctx, "input", strings.Join(input, "\n\n")); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := g.BaseProvider.Invoke(ctx, template, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.options.output {
+		if err := g.moderate(ctx, "output", response.GetContent()); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// moderate returns a ModerationError when the moderator flags text.
+func (g *moderationGuard) moderate(ctx context.Context, stage, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	moderation, err := g.moderator.Moderate(ctx, text)
+	if err != nil {
+		return errorbank.NewMessageError("moderation", "failed to moderate "+stage, err)
+	}
+	if moderation.Flagged {
+		return errorbank.NewModerationError(stage, moderation.FlaggedCategories())
+	}
+	return nil
+}
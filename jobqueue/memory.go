@@ -0,0 +1,124 @@
+package jobqueue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory priority Queue. Jobs are lost when the process
+// exits, so it suits development and workloads that can be resubmitted.
+type Memory struct {
+	mu     sync.Mutex
+	jobs   jobHeap
+	seq    uint64
+	wait   chan struct{}
+	closed bool
+}
+
+// NewMemory creates an empty in-memory queue.
+//
+// Example:
+//
+//	queue := jobqueue.NewMemory()
+//	err := queue.Enqueue(ctx, jobqueue.Job{ID: "summary-42", Template: prompt, Priority: 10})
+func NewMemory() *Memory {
+	return &Memory{wait: make(chan struct{})}
+}
+
+// Enqueue adds a job to the queue.
+func (m *Memory) Enqueue(ctx context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+	m.seq++
+	heap.Push(&m.jobs, queuedJob{job: job, seq: m.seq})
+
+	// Wake every waiting Dequeue; the first to take the lock gets the job.
+	close(m.wait)
+	m.wait = make(chan struct{})
+	return nil
+}
+
+// Dequeue removes and returns the job with the highest priority.
+func (m *Memory) Dequeue(ctx context.Context) (Job, error) {
+	for {
+		m.mu.Lock()
+		if m.jobs.Len() > 0 {
+			job := heap.Pop(&m.jobs).(queuedJob).job
+			m.mu.Unlock()
+			return job, nil
+		}
+		if m.closed {
+			m.mu.Unlock()
+			return Job{}, ErrClosed
+		}
+		wait := m.wait
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Job{}, ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// Close stops the queue from accepting jobs. Jobs already enqueued can
+// still be dequeued; Dequeue returns ErrClosed once the queue is empty.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.closed {
+		m.closed = true
+		close(m.wait)
+	}
+	return nil
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.jobs.Len()
+}
+
+// queuedJob is a job with its enqueue sequence number, which keeps jobs of
+// equal priority in FIFO order.
+type queuedJob struct {
+	job Job
+	seq uint64
+}
+
+// jobHeap implements heap.Interface ordered by priority, then sequence.
+type jobHeap []queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority > h[j].job.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) { *h = append(*h, x.(queuedJob)) }
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
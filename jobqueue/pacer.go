@@ -0,0 +1,135 @@
+package jobqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pacer slows down every worker of a pool while its provider is rate
+// limiting. A rate-limited response pauses all workers for a backoff that
+// doubles with each consecutive rate limit. When the pause ends a single
+// probe request is let through; the other workers resume only once the probe
+// succeeds, so a pool recovering from an outage does not stampede the
+// provider with its whole backlog.
+type pacer struct {
+	mu       sync.Mutex
+	initial  time.Duration
+	max      time.Duration
+	backoff  time.Duration
+	until    time.Time
+	probing  bool
+	released chan struct{}
+}
+
+// newPacer creates a pacer. An initial backoff of zero disables pacing.
+func newPacer(initial, max time.Duration) *pacer {
+	return &pacer{
+		initial:  initial,
+		max:      max,
+		released: make(chan struct{}),
+	}
+}
+
+// wait blocks until the caller may send a request. It returns true when the
+// caller is the recovery probe and must report its outcome with success or
+// rateLimited.
+func (p *pacer) wait(ctx context.Context) (probe bool, err error) {
+	for {
+		p.mu.Lock()
+		if p.backoff == 0 {
+			p.mu.Unlock()
+			return false, nil
+		}
+
+		released := p.released
+		delay := time.Until(p.until)
+		if delay <= 0 && !p.probing {
+			p.probing = true
+			p.mu.Unlock()
+			return true, nil
+		}
+		p.mu.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if delay > 0 {
+			timer = time.NewTimer(delay)
+			timeout = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-released:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// success ends the backoff after a successful probe and releases every
+// waiting worker.
+func (p *pacer) success(probe bool) {
+	if !probe {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.backoff = 0
+	p.probing = false
+	p.release()
+}
+
+// rateLimited starts or extends the backoff. Only the probe is let through
+// during a backoff, so rate limits of other requests were already in flight
+// when it started and do not extend it further.
+func (p *pacer) rateLimited(probe bool) {
+	if p.initial == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !probe && p.backoff > 0 {
+		return
+	}
+
+	switch {
+	case p.backoff == 0:
+		p.backoff = p.initial
+	case p.backoff < p.max:
+		p.backoff = min(p.backoff*2, p.max)
+	}
+	p.until = time.Now().Add(p.backoff)
+	p.probing = false
+	p.release()
+}
+
+// failed lets another worker probe when the probe failed for a reason other
+// than a rate limit.
+func (p *pacer) failed(probe bool) {
+	if !probe {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.probing = false
+	p.release()
+}
+
+// release wakes every waiting worker so it re-evaluates the pacer state.
+func (p *pacer) release() {
+	close(p.released)
+	p.released = make(chan struct{})
+}
@@ -0,0 +1,240 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+)
+
+// poolOptions contains configuration options for a Pool.
+type poolOptions struct {
+	workers     int
+	maxAttempts int
+	retryDelay  time.Duration
+	onResult    func(Result)
+	store       ResultStore
+
+	rateLimitBackoff    time.Duration
+	maxRateLimitBackoff time.Duration
+}
+
+// PoolOption is a function type that modifies pool options.
+type PoolOption func(*poolOptions)
+
+// WithWorkers sets the number of jobs processed concurrently.
+func WithWorkers(workers int) PoolOption {
+	return func(p *poolOptions) {
+		p.workers = workers
+	}
+}
+
+// WithMaxAttempts sets how many times a job is attempted when the job does
+// not set MaxAttempts itself.
+func WithMaxAttempts(maxAttempts int) PoolOption {
+	return func(p *poolOptions) {
+		p.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryDelay sets how long a failed job waits before it is enqueued
+// again. The delay grows linearly with the number of attempts.
+func WithRetryDelay(retryDelay time.Duration) PoolOption {
+	return func(p *poolOptions) {
+		p.retryDelay = retryDelay
+	}
+}
+
+// WithRateLimitBackoff sets how the pool slows down when the provider
+// responds with 429 Too Many Requests. A rate limit pauses every worker, not
+// just the one whose request failed, for a backoff that starts at initial
+// and doubles with each consecutive rate limit up to max. After the pause a
+// single request probes the provider, and the other workers resume once it
+// succeeds. An initial backoff of zero disables the slowdown.
+//
+// Example:
+//
+//	pool := jobqueue.NewPool(queue, provider,
+//	  jobqueue.WithRateLimitBackoff(500*time.Millisecond, 30*time.Second),
+//	)
+func WithRateLimitBackoff(initial, max time.Duration) PoolOption {
+	return func(p *poolOptions) {
+		p.rateLimitBackoff = initial
+		p.maxRateLimitBackoff = max
+	}
+}
+
+// WithResultHandler sets the callback that receives the outcome of every
+// job, once it succeeds or has exhausted its attempts. The handler is
+// called from the worker goroutines and must be safe for concurrent use.
+//
+// Example:
+//
+//	pool := jobqueue.NewPool(queue, provider, jobqueue.WithResultHandler(func(result jobqueue.Result) {
+//	  if result.Err != nil {
+//	    log.Printf("job %s failed: %v", result.Job.ID, result.Err)
+//	  }
+//	}))
+func WithResultHandler(onResult func(Result)) PoolOption {
+	return func(p *poolOptions) {
+		p.onResult = onResult
+	}
+}
+
+// WithResultStore makes jobs with an IdempotencyKey idempotent: successful
+// results are stored under the key, and a job whose key already has a
+// stored result completes with it without invoking the provider.
+//
+// Example:
+//
+//	pool := jobqueue.NewPool(queue, provider,
+//	  jobqueue.WithResultStore(jobqueue.NewMemoryResultStore(time.Hour)),
+//	)
+func WithResultStore(store ResultStore) PoolOption {
+	return func(p *poolOptions) {
+		p.store = store
+	}
+}
+
+// Pool is a set of workers that take jobs from a Queue and invoke a provider.
+type Pool struct {
+	queue    Queue
+	provider llm.BaseProvider
+	options  poolOptions
+	pacer    *pacer
+	retries  sync.WaitGroup
+}
+
+// NewPool creates a worker pool. Call Run to start processing jobs.
+//
+// Example:
+//
+//	queue := jobqueue.NewMemory()
+//	pool := jobqueue.NewPool(queue, provider, jobqueue.WithWorkers(8))
+//	go pool.Run(ctx)
+func NewPool(queue Queue, provider llm.BaseProvider, options ...PoolOption) *Pool {
+	opts := poolOptions{
+		workers:     4,
+		maxAttempts: 3,
+		retryDelay:  time.Second,
+
+		rateLimitBackoff:    time.Second,
+		maxRateLimitBackoff: time.Minute,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &Pool{
+		queue:    queue,
+		provider: provider,
+		options:  opts,
+		pacer:    newPacer(opts.rateLimitBackoff, opts.maxRateLimitBackoff),
+	}
+}
+
+// Run processes jobs until the context is cancelled or the queue is closed
+// and drained. It blocks until every worker has stopped. Jobs still waiting
+// for a retry when the queue is closed fail with ErrClosed.
+func (p *Pool) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < p.options.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			p.work(ctx)
+		}()
+	}
+	workers.Wait()
+	p.retries.Wait()
+}
+
+// work is the loop of a single worker.
+func (p *Pool) work(ctx context.Context) {
+	for {
+		job, err := p.queue.Dequeue(ctx)
+		if err != nil {
+			return
+		}
+		p.process(ctx, job)
+	}
+}
+
+// process invokes the provider for a job and either reports the result or
+// schedules a retry.
+func (p *Pool) process(ctx context.Context, job Job) {
+	store := p.options.store
+	if store != nil && job.IdempotencyKey != "" {
+		stored, ok, err := store.Get(ctx, job.IdempotencyKey)
+		if err != nil {
+			p.report(Result{Job: job, Err: err})
+			return
+		}
+		if ok {
+			p.report(Result{Job: job, Message: stored.Message(), Stored: true})
+			return
+		}
+	}
+
+	probe, err := p.pacer.wait(ctx)
+	if err != nil {
+		p.report(Result{Job: job, Err: err})
+		return
+	}
+
+	job.Attempts++
+	response, err := p.provider.Invoke(ctx, job.Template, job.Options...)
+	switch {
+	case err == nil:
+		p.pacer.success(probe)
+	case llm.IsRateLimited(err):
+		p.pacer.rateLimited(probe)
+	default:
+		p.pacer.failed(probe)
+	}
+
+	if err == nil {
+		if store != nil && job.IdempotencyKey != "" {
+			err = store.Put(ctx, newStoredResult(job.IdempotencyKey, job, response))
+		}
+		p.report(Result{Job: job, Message: response, Err: err})
+		return
+	}
+
+	maxAttempts := job.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = p.options.maxAttempts
+	}
+	if job.Attempts >= maxAttempts || ctx.Err() != nil {
+		p.report(Result{Job: job, Err: err})
+		return
+	}
+
+	p.retries.Add(1)
+	go func() {
+		defer p.retries.Done()
+
+		timer := time.NewTimer(p.options.retryDelay * time.Duration(job.Attempts))
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			p.report(Result{Job: job, Err: err})
+			return
+		case <-timer.C:
+		}
+
+		if enqueueErr := p.queue.Enqueue(ctx, job); enqueueErr != nil {
+			p.report(Result{Job: job, Err: errors.Join(err, enqueueErr)})
+		}
+	}()
+}
+
+// report passes a result to the result handler, if any.
+func (p *Pool) report(result Result) {
+	if p.options.onResult != nil {
+		p.options.onResult(result)
+	}
+}
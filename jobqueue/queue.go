@@ -0,0 +1,67 @@
+// Package jobqueue runs generation jobs in the background, decoupling web
+// requests from slow LLM work. Jobs are enqueued on a Queue and processed
+// by a Pool of workers that invoke a provider and retry failed jobs.
+package jobqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// ErrClosed is returned by a Queue that has been closed.
+var ErrClosed = errorbank.NewMessageError("queue_closed", "queue is closed", nil)
+
+// Job is a single generation request.
+type Job struct {
+	// ID identifies the job in results and logs.
+	ID string
+
+	// Template is the rendered template sent to the provider.
+	Template template.Template
+
+	// Options are the invoke options of the request.
+	Options []llm.InvokeOption
+
+	// IdempotencyKey identifies the work of the job across resubmissions.
+	// When the Pool has a ResultStore, a job whose key already has a stored
+	// result completes with that result without invoking the provider.
+	IdempotencyKey string
+
+	// Priority orders jobs in the queue; higher priorities are processed first.
+	// Jobs of equal priority are processed in the order they were enqueued.
+	Priority int
+
+	// MaxAttempts is how many times the job is attempted before it fails.
+	// Zero uses the default of the Pool.
+	MaxAttempts int
+
+	// Attempts is the number of attempts made so far.
+	Attempts int
+
+	// EnqueuedAt is when the job was first enqueued.
+	EnqueuedAt time.Time
+}
+
+// Result is the outcome of a job. Message is set when the job succeeded and
+// Err when it failed or its result could not be stored. Stored is true when the message was read from the ResultStore instead of
+// being generated.
+type Result struct {
+	Job     Job
+	Message message.Message
+	Err     error
+	Stored  bool
+}
+
+// Queue defines the interface for job queue backends.
+// Dequeue blocks until a job is available, the context is cancelled,
+// or the queue is closed, in which case it returns ErrClosed.
+type Queue interface {
+	Enqueue(ctx context.Context, job Job) error
+	Dequeue(ctx context.Context) (Job, error)
+	Close() error
+}
@@ -0,0 +1,198 @@
+package jobqueue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// StoredResult is the successful outcome of a job, kept under the job's
+// idempotency key.
+type StoredResult struct {
+	Key              string    `json:"key"`
+	JobID            string    `json:"job_id"`
+	Content          string    `json:"content"`
+	Reasoning        string    `json:"reasoning,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CreatedAt        time.Time `json:"created_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// Message rebuilds the assistant message of the stored result.
+func (r StoredResult) Message() message.Message {
+	return message.FromAssistant(r.Content,
+		message.WithUsage(r.PromptTokens, r.CompletionTokens, r.TotalTokens),
+		message.WithReasoning(r.Reasoning),
+	)
+}
+
+// expired reports whether the result has outlived its TTL.
+func (r StoredResult) expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && now.After(r.ExpiresAt)
+}
+
+// newStoredResult captures a successful job outcome.
+func newStoredResult(key string, job Job, response message.Message) StoredResult {
+	usage := response.GetUsage()
+	return StoredResult{
+		Key:              key,
+		JobID:            job.ID,
+		Content:          response.GetContent(),
+		Reasoning:        response.GetReasoning(),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CreatedAt:        time.Now(),
+	}
+}
+
+// withTTL sets the expiry of a result that does not have one yet.
+func (r StoredResult) withTTL(ttl time.Duration) StoredResult {
+	if ttl > 0 && r.ExpiresAt.IsZero() {
+		r.ExpiresAt = r.CreatedAt.Add(ttl)
+	}
+	return r
+}
+
+// ResultStore keeps job results by idempotency key, so a resubmitted job
+// returns the stored result instead of invoking the model again.
+// Get reports false when no unexpired result is stored under the key.
+// Put sets the expiry of results from the TTL of the store.
+type ResultStore interface {
+	Get(ctx context.Context, key string) (StoredResult, bool, error)
+	Put(ctx context.Context, result StoredResult) error
+}
+
+// MemoryResultStore keeps results in memory. Results survive client retries
+// but not a restart of the process; use FileResultStore for that.
+type MemoryResultStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	results map[string]StoredResult
+}
+
+// NewMemoryResultStore creates an in-memory store whose results expire after
+// ttl. A ttl of zero keeps results forever.
+func NewMemoryResultStore(ttl time.Duration) *MemoryResultStore {
+	return &MemoryResultStore{
+		ttl:     ttl,
+		results: make(map[string]StoredResult),
+	}
+}
+
+// Get returns the result stored under key.
+func (s *MemoryResultStore) Get(ctx context.Context, key string) (StoredResult, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, ok := s.results[key]
+	if !ok {
+		return StoredResult{}, false, nil
+	}
+	if result.expired(time.Now()) {
+		delete(s.results, key)
+		return StoredResult{}, false, nil
+	}
+	return result, true, nil
+}
+
+// Put stores a result under its key.
+func (s *MemoryResultStore) Put(ctx context.Context, result StoredResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[result.Key] = result.withTTL(s.ttl)
+	return nil
+}
+
+// FileResultStore keeps each result as a JSON file in a directory, so results
+// survive crashes and restarts. Files are written atomically and named after
+// the hash of the key.
+type FileResultStore struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileResultStore creates a store in dir, creating the directory if needed.
+// Results expire after ttl; a ttl of zero keeps results forever.
+//
+// Example:
+//
+//	store, err := jobqueue.NewFileResultStore("/var/lib/myapp/results", 24*time.Hour)
+//	pool := jobqueue.NewPool(queue, provider, jobqueue.WithResultStore(store))
+func NewFileResultStore(dir string, ttl time.Duration) (*FileResultStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errorbank.NewMessageError("result_store", "failed to create result directory", err)
+	}
+	return &FileResultStore{dir: dir, ttl: ttl}, nil
+}
+
+// Get returns the result stored under key. Expired results are removed.
+func (s *FileResultStore) Get(ctx context.Context, key string) (StoredResult, bool, error) {
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return StoredResult{}, false, nil
+	}
+	if err != nil {
+		return StoredResult{}, false, errorbank.NewMessageError("result_store", "failed to read result", err)
+	}
+
+	var result StoredResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return StoredResult{}, false, errorbank.NewMessageError("result_store", "failed to decode result", err)
+	}
+	if result.expired(time.Now()) {
+		_ = os.Remove(path)
+		return StoredResult{}, false, nil
+	}
+	return result, true, nil
+}
+
+// Put stores a result under its key, replacing any previous result.
+func (s *FileResultStore) Put(ctx context.Context, result StoredResult) error {
+	data, err := json.Marshal(result.withTTL(s.ttl))
+	if err != nil {
+		return errorbank.NewMessageError("result_store", "failed to encode result", err)
+	}
+
+	file, err := os.CreateTemp(s.dir, ".result-*")
+	if err != nil {
+		return errorbank.NewMessageError("result_store", "failed to write result", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return errorbank.NewMessageError("result_store", "failed to write result", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return errorbank.NewMessageError("result_store", "failed to write result", err)
+	}
+	if err := file.Close(); err != nil {
+		return errorbank.NewMessageError("result_store", "failed to write result", err)
+	}
+
+	if err := os.Rename(file.Name(), s.path(result.Key)); err != nil {
+		return errorbank.NewMessageError("result_store", "failed to write result", err)
+	}
+	return nil
+}
+
+// path returns the file that holds the result of key.
+func (s *FileResultStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
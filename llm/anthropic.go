@@ -2,7 +2,10 @@ package llm
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/bpradana/failsafe"
@@ -13,6 +16,15 @@ import (
 	"github.com/bpradana/tars/template"
 )
 
+// anthropicVersion is the Anthropic API version sent with every request.
+const anthropicVersion = "2023-06-01"
+
+// anthropicFilesBeta enables document blocks that reference uploaded files.
+const anthropicFilesBeta = "files-api-2025-04-14"
+
+// anthropicSchemaTool is the name of the tool used to obtain structured output.
+const anthropicSchemaTool = "structured_output"
+
 // AnthropicProvider implements the BaseProvider interface for Anthropic
 type AnthropicProvider struct {
 	baseProvider
@@ -21,10 +33,11 @@ type AnthropicProvider struct {
 // NewAnthropic creates a new Anthropic provider
 func NewAnthropic(options ...LLMOption) BaseProvider {
 	opts := llmOptions{
-		baseURL:     "https://api.anthropic.com",
-		timeout:     10 * time.Second,
-		maxAttempts: 1,
-		maxDelay:    0 * time.Second,
+		baseURL:      "https://api.anthropic.com",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
 	}
 
 	for _, option := range options {
@@ -36,12 +49,13 @@ func NewAnthropic(options ...LLMOption) BaseProvider {
 			options: opts,
 			client: httpx.NewClient().
 				WithBaseURL(opts.baseURL).
-				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
-				WithTimeout(opts.timeout),
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
 			retrier: failsafe.NewRetrier(
 				failsafe.WithMaxAttempts(opts.maxAttempts),
 				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
 			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
 		},
 	}
 }
@@ -57,6 +71,9 @@ func (a *AnthropicProvider) Invoke(ctx context.Context, template template.Templa
 	if err := template.Validate(); err != nil {
 		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
 	}
+	if err := anthropicParts.check("Anthropic", template); err != nil {
+		return nil, err
+	}
 
 	opts := invokeOptions{
 		model:       "claude-3-5-sonnet-20240620",
@@ -67,67 +84,295 @@ func (a *AnthropicProvider) Invoke(ctx context.Context, template template.Templa
 		option(&opts)
 	}
 
+	opts.applyDeadline(ctx)
+
+	if err := validateShape(anthropicShape, template, opts); err != nil {
+		return nil, err
+	}
+
+	request := newAnthropicParams(template, opts)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
 	// Validate required configuration
 	if a.options.apiKey == "" {
 		return nil, errorbank.NewValidationError("api_key", "Anthropic API key is required", "")
 	}
 
-	resp, err := failsafe.RetryWithResult(ctx, a.retrier, func() (*httpx.Response, error) {
-		return a.client.Post("/chat/completions", ChatCompletionsRequest{
-			Model: opts.model,
-			Messages: func() []Message {
-				templateMessages := template.GetMessage()
-				msgs := make([]Message, len(templateMessages))
-				for i, msg := range templateMessages {
-					msgs[i] = Message{
-						Role:    string(msg.GetRole()),
-						Content: msg.GetContent(),
-					}
-				}
-				return msgs
-			}(),
-			ResponseFormat: func() *ResponseFormat {
-				if opts.jsonSchema != nil {
-					return &ResponseFormat{
-						Type: "json_schema",
-						JsonSchema: JsonSchema{
-							Name:   "schema",
-							Strict: true,
-							Schema: opts.jsonSchema,
-						},
-					}
-				}
-				return nil
-			}(),
-		})
-	})
-	if err != nil {
-		return nil, errorbank.NewMessageError("http_request", "failed to create request", err)
+	if err := a.checkHealth(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result ChatCompletionsResponse
-	if err := resp.Decode(&result); err != nil {
-		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	var result anthropicMessageResponse
+	err := a.native(ctx, http.MethodPost, "/v1/messages", request, func(resp *httpx.Response) error {
+		return resp.Decode(&result)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if len(result.Choices) == 0 {
-		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	content := result.text()
+	if content == "" {
+		return nil, errorbank.NewMessageError("no_choices", "no content in response", nil)
 	}
 
 	if opts.jsonSchema != nil {
-		err = json.Unmarshal([]byte(result.Choices[0].Message.Content), opts.structuredOutput)
-		if err != nil {
-			return nil, errorbank.NewMessageError("json_unmarshal", "failed to unmarshal structured output", err)
+		if err := decodeStructuredOutput(content, opts); err != nil {
+			return nil, err
 		}
 	}
 
 	return message.FromAssistant(
-		result.Choices[0].Message.Content,
+		content,
 		message.WithUsage(
-			result.Usage.PromptTokens,
-			result.Usage.CompletionTokens,
-			result.Usage.TotalTokens,
+			result.Usage.InputTokens,
+			result.Usage.OutputTokens,
+			result.Usage.InputTokens+result.Usage.OutputTokens,
 		),
 	), nil
 }
+
+// anthropicSource is the base64 payload or uploaded file of a document or image content block.
+type anthropicSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	FileID    string `json:"file_id,omitempty"`
+}
+
+// anthropicContentBlock is an element of a native message content array.
+type anthropicContentBlock struct {
+	Type   string           `json:"type"`
+	Text   string           `json:"text,omitempty"`
+	Source *anthropicSource `json:"source,omitempty"`
+	Title  string           `json:"title,omitempty"`
+}
+
+// anthropicMessage is a message in Anthropic's native Messages API format.
+// Content is either a string or a slice of content blocks.
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+// anthropicTool declares a tool the model can call.
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// anthropicToolChoice forces the model to call a specific tool.
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// anthropicMessageParams is the body of a native Messages API request.
+type anthropicMessageParams struct {
+	Model       string               `json:"model"`
+	MaxTokens   int                  `json:"max_tokens"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	Temperature float64              `json:"temperature"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// usesFiles reports whether any message references an uploaded file.
+func (p anthropicMessageParams) usesFiles() bool {
+	for _, msg := range p.Messages {
+		blocks, _ := msg.Content.([]anthropicContentBlock)
+		for _, block := range blocks {
+			if block.Source != nil && block.Source.FileID != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anthropicMessageResponse is the body of a native Messages API response.
+type anthropicMessageResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// text returns the text of the response, or the input of the structured
+// output tool call when structured output was requested.
+func (r anthropicMessageResponse) text() string {
+	var content strings.Builder
+	for _, block := range r.Content {
+		switch block.Type {
+		case "text":
+			content.WriteString(block.Text)
+		case "tool_use":
+			if block.Name == anthropicSchemaTool {
+				content.Write(block.Input)
+			}
+		}
+	}
+	return content.String()
+}
+
+// newAnthropicParams converts a template into a native Messages API request.
+// System messages are moved to the system field, and structured output is
+// obtained by forcing a call to a tool whose input schema is the output schema.
+func newAnthropicParams(template template.Template, opts invokeOptions) anthropicMessageParams {
+	params := anthropicMessageParams{
+		Model:       opts.model,
+		MaxTokens:   opts.maxTokens,
+		Temperature: opts.temperature,
+	}
+
+	var system []string
+	for _, msg := range opts.messages(template) {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+
+		if msg.GetRole() == message.RoleSystem {
+			system = append(system, content)
+			continue
+		}
+
+		params.Messages = append(params.Messages, anthropicMessage{
+			Role:    string(msg.GetRole()),
+			Content: newAnthropicContent(content, msg.GetParts()),
+		})
+	}
+	params.System = strings.Join(system, "\n\n")
+
+	if opts.jsonSchema != nil {
+		params.Tools = []anthropicTool{{
+			Name:        anthropicSchemaTool,
+			Description: "Respond with output that matches this schema.",
+			InputSchema: opts.jsonSchema,
+		}}
+		params.ToolChoice = &anthropicToolChoice{Type: "tool", Name: anthropicSchemaTool}
+	}
+
+	return params
+}
+
+// newAnthropicContent returns the plain text content, or content blocks
+// with documents and images placed before the text when the message has them.
+func newAnthropicContent(content string, parts []message.Part) any {
+	var blocks []anthropicContentBlock
+	for _, part := range parts {
+		switch part.Type {
+		case message.PartDocument:
+			source := &anthropicSource{Type: "file", FileID: part.FileID}
+			if part.FileID == "" {
+				source = &anthropicSource{
+					Type:      "base64",
+					MediaType: "application/pdf",
+					Data:      base64.StdEncoding.EncodeToString(part.Data),
+				}
+			}
+
+			blocks = append(blocks, anthropicContentBlock{
+				Type:   "document",
+				Source: source,
+				Title:  part.Name,
+			})
+		case message.PartImage:
+			blocks = append(blocks, anthropicContentBlock{
+				Type: "image",
+				Source: &anthropicSource{
+					Type:      "base64",
+					MediaType: "image/" + part.Format,
+					Data:      base64.StdEncoding.EncodeToString(part.Data),
+				},
+			})
+		}
+	}
+
+	if len(blocks) == 0 {
+		return content
+	}
+
+	if content != "" {
+		blocks = append(blocks, anthropicContentBlock{Type: "text", Text: content})
+	}
+	return blocks
+}
+
+// native sends a request to Anthropic's native API, which authenticates
+// with the x-api-key header instead of a bearer token.
+func (a *AnthropicProvider) native(ctx context.Context, method, path string, body any, decode func(*httpx.Response) error) error {
+	return a.call(ctx, method, path, body, false, decode)
+}
+
+// nativeStream is native for large responses, such as batch results, whose
+// successful body is left on the connection for decode to read from
+// resp.Body incrementally instead of being buffered in memory.
+func (a *AnthropicProvider) nativeStream(ctx context.Context, path string, decode func(*httpx.Response) error) error {
+	return a.call(ctx, http.MethodGet, path, nil, true, decode)
+}
+
+// call sends a request to Anthropic's native API and decodes the response.
+func (a *AnthropicProvider) call(ctx context.Context, method, path string, body any, stream bool, decode func(*httpx.Response) error) error {
+	resp, err := failsafe.RetryWithResult(ctx, a.retrier, func() (*httpx.Response, error) {
+		if a.keys == nil {
+			return a.nativeRequest(method, path, body, a.options.apiKey, stream)
+		}
+		return a.withKeys(func(key string) (*httpx.Response, error) {
+			return a.nativeRequest(method, path, body, key, stream)
+		})
+	})
+	if err != nil {
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := a.checkResponse(resp); err != nil {
+		return err
+	}
+
+	if err := decode(resp); err != nil {
+		return errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+	return nil
+}
+
+// nativeRequest sends a single request to Anthropic's native API with the given key.
+func (a *AnthropicProvider) nativeRequest(method, path string, body any, key string, stream bool) (*httpx.Response, error) {
+	var req *httpx.Request
+	var err error
+	if method == http.MethodPost {
+		req, err = a.client.POST(path)
+	} else {
+		req, err = a.client.GET(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req.WithHeader("x-api-key", key).
+		WithHeader("anthropic-version", anthropicVersion)
+	if params, ok := body.(anthropicMessageParams); ok && params.usesFiles() {
+		req.WithHeader("anthropic-beta", anthropicFilesBeta)
+	}
+	if body != nil {
+		req.WithJSON(body)
+	}
+	if stream {
+		req.WithStreamingDecode(true)
+	}
+	return req.Do()
+}
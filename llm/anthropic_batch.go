@@ -0,0 +1,265 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/pkg/webhook"
+	"github.com/bpradana/tars/template"
+)
+
+// anthropicBatchRequest is a single request of a message batch.
+type anthropicBatchRequest struct {
+	CustomID string                 `json:"custom_id"`
+	Params   anthropicMessageParams `json:"params"`
+}
+
+// AnthropicBatchCounts tallies the requests of a batch by status.
+type AnthropicBatchCounts struct {
+	Processing int `json:"processing"`
+	Succeeded  int `json:"succeeded"`
+	Errored    int `json:"errored"`
+	Canceled   int `json:"canceled"`
+	Expired    int `json:"expired"`
+}
+
+// AnthropicBatch describes a message batch and its processing status.
+type AnthropicBatch struct {
+	ID               string               `json:"id"`
+	ProcessingStatus string               `json:"processing_status"`
+	RequestCounts    AnthropicBatchCounts `json:"request_counts"`
+	CreatedAt        time.Time            `json:"created_at"`
+	EndedAt          *time.Time           `json:"ended_at"`
+	ExpiresAt        time.Time            `json:"expires_at"`
+	ResultsURL       string               `json:"results_url"`
+}
+
+// AnthropicBatchResult is the outcome of one template of a batch.
+// Exactly one of Message and Err is set.
+type AnthropicBatchResult struct {
+	// Index is the position of the template in the slice given to CreateBatch.
+	Index int
+
+	Message message.Message
+	Err     error
+}
+
+// anthropicBatchResultLine is a single line of the batch results file.
+type anthropicBatchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string                   `json:"type"`
+		Message anthropicMessageResponse `json:"message"`
+		Error   json.RawMessage          `json:"error"`
+	} `json:"result"`
+}
+
+// CreateBatch submits the templates as an Anthropic Message Batch, which is
+// processed asynchronously at a discount. Each template becomes one request
+// whose results can be fetched with BatchResults once the batch has ended.
+//
+// Example:
+//
+//	batch, err := provider.CreateBatch(ctx, templates, WithModel("claude-3-5-haiku-latest"))
+//	batch, err = provider.WaitBatch(ctx, batch.ID, time.Minute)
+//	results, err := provider.BatchResults(ctx, batch.ID)
+func (a *AnthropicProvider) CreateBatch(ctx context.Context, templates []template.Template, options ...InvokeOption) (AnthropicBatch, error) {
+	opts := invokeOptions{
+		model:       "claude-3-5-sonnet-20240620",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if a.options.apiKey == "" {
+		return AnthropicBatch{}, errorbank.NewValidationError("api_key", "Anthropic API key is required", "")
+	}
+
+	requests := make([]anthropicBatchRequest, len(templates))
+	for i, tmpl := range templates {
+		if err := tmpl.Validate(); err != nil {
+			return AnthropicBatch{}, errorbank.NewTemplateError(fmt.Sprintf("templates[%d]", i), "invalid template provided", err)
+		}
+		if err := anthropicParts.check("Anthropic", tmpl); err != nil {
+			return AnthropicBatch{}, errorbank.NewTemplateError(fmt.Sprintf("templates[%d]", i), "unsupported message part", err)
+		}
+		if err := validateShape(anthropicShape, tmpl, opts); err != nil {
+			return AnthropicBatch{}, errorbank.NewTemplateError(fmt.Sprintf("templates[%d]", i), "conversation shape not accepted", err)
+		}
+
+		requests[i] = anthropicBatchRequest{
+			CustomID: "request-" + strconv.Itoa(i),
+			Params:   newAnthropicParams(tmpl, opts),
+		}
+	}
+
+	var batch AnthropicBatch
+	err := a.native(ctx, http.MethodPost, "/v1/messages/batches", map[string]any{"requests": requests}, func(resp *httpx.Response) error {
+		return resp.Decode(&batch)
+	})
+	return batch, err
+}
+
+// GetBatch returns the current status of a batch.
+func (a *AnthropicProvider) GetBatch(ctx context.Context, id string) (AnthropicBatch, error) {
+	var batch AnthropicBatch
+	err := a.native(ctx, http.MethodGet, "/v1/messages/batches/"+id, nil, func(resp *httpx.Response) error {
+		return resp.Decode(&batch)
+	})
+	return batch, err
+}
+
+// WaitBatch polls the batch every interval until its processing has ended
+// or the context is cancelled.
+func (a *AnthropicProvider) WaitBatch(ctx context.Context, id string, interval time.Duration) (AnthropicBatch, error) {
+	if interval <= 0 {
+		return AnthropicBatch{}, errorbank.NewValidationError("interval", "must be positive", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		batch, err := a.GetBatch(ctx, id)
+		if err != nil || batch.ProcessingStatus == "ended" {
+			return batch, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// BatchResults downloads the results of an ended batch, ordered by the
+// position of the templates given to CreateBatch. The results file is
+// decoded line by line as it streams in, so it is never held in memory
+// as a whole.
+func (a *AnthropicProvider) BatchResults(ctx context.Context, id string) ([]AnthropicBatchResult, error) {
+	var results []AnthropicBatchResult
+	err := a.nativeStream(ctx, "/v1/messages/batches/"+id+"/results", func(resp *httpx.Response) error {
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+
+			var line anthropicBatchResultLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				return err
+			}
+			results = append(results, newAnthropicBatchResult(line))
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]AnthropicBatchResult, 0, len(results))
+	byIndex := make(map[int]AnthropicBatchResult, len(results))
+	maxIndex := -1
+	for _, result := range results {
+		byIndex[result.Index] = result
+		maxIndex = max(maxIndex, result.Index)
+	}
+	for i := 0; i <= maxIndex; i++ {
+		if result, ok := byIndex[i]; ok {
+			ordered = append(ordered, result)
+		}
+	}
+	return ordered, nil
+}
+
+// AnthropicBatchNotification is the payload delivered by NotifyBatch.
+type AnthropicBatchNotification struct {
+	Batch   AnthropicBatch                     `json:"batch"`
+	Results []AnthropicBatchNotificationResult `json:"results"`
+}
+
+// AnthropicBatchNotificationResult is the outcome of one template of a
+// batch in a webhook payload. Exactly one of Content and Error is set.
+type AnthropicBatchNotificationResult struct {
+	Index            int    `json:"index"`
+	Content          string `json:"content,omitempty"`
+	PromptTokens     int    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int    `json:"completion_tokens,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// NotifyBatch waits for a batch to end and delivers its results to the
+// webhook as a "batch.completed" event. It blocks until the delivery
+// succeeds, its retries are exhausted, or the context is cancelled, so it
+// is usually started in its own goroutine.
+//
+// Example:
+//
+//	batch, err := provider.CreateBatch(ctx, templates)
+//	hook := webhook.New("https://example.com/hooks/batches", secret)
+//	go func() {
+//	  if err := provider.NotifyBatch(ctx, batch.ID, time.Minute, hook); err != nil {
+//	    log.Printf("batch notification failed: %v", err)
+//	  }
+//	}()
+func (a *AnthropicProvider) NotifyBatch(ctx context.Context, id string, interval time.Duration, hook *webhook.Webhook) error {
+	batch, err := a.WaitBatch(ctx, id, interval)
+	if err != nil {
+		return err
+	}
+
+	results, err := a.BatchResults(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	notification := AnthropicBatchNotification{
+		Batch:   batch,
+		Results: make([]AnthropicBatchNotificationResult, len(results)),
+	}
+	for i, result := range results {
+		entry := AnthropicBatchNotificationResult{Index: result.Index}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		} else {
+			usage := result.Message.GetUsage()
+			entry.Content = result.Message.GetContent()
+			entry.PromptTokens = usage.PromptTokens
+			entry.CompletionTokens = usage.CompletionTokens
+		}
+		notification.Results[i] = entry
+	}
+
+	return hook.Deliver(ctx, "batch.completed", notification)
+}
+
+// newAnthropicBatchResult converts a results file line into a batch result.
+func newAnthropicBatchResult(line anthropicBatchResultLine) AnthropicBatchResult {
+	index, _ := strconv.Atoi(strings.TrimPrefix(line.CustomID, "request-"))
+	result := AnthropicBatchResult{Index: index}
+
+	if line.Result.Type != "succeeded" {
+		result.Err = errorbank.NewMessageError("batch_"+line.Result.Type, string(line.Result.Error), nil)
+		return result
+	}
+
+	usage := line.Result.Message.Usage
+	result.Message = message.FromAssistant(
+		line.Result.Message.text(),
+		message.WithUsage(usage.InputTokens, usage.OutputTokens, usage.InputTokens+usage.OutputTokens),
+	)
+	return result
+}
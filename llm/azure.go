@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// AzureOpenAIProvider implements the BaseProvider interface for Azure OpenAI
+type AzureOpenAIProvider struct {
+	baseProvider
+}
+
+// NewAzureOpenAI creates a new Azure OpenAI provider.
+// The base URL is the resource endpoint, e.g. https://my-resource.openai.azure.com,
+// and requests are routed to the deployment set with WithDeployment.
+func NewAzureOpenAI(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+		apiVersion:   "2024-10-21",
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &AzureOpenAIProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Set("api-key", opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys:         newKeyPool(opts.apiKeys, opts.keyStrategy),
+			apiKeyHeader: "api-key",
+		},
+	}
+}
+
+// GetName returns the provider name
+func (a *AzureOpenAIProvider) GetName() string {
+	return "azure-openai"
+}
+
+// Invoke implements the BaseProvider interface for Azure OpenAI
+func (a *AzureOpenAIProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := openAIParts.check("Azure OpenAI", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       a.options.deployment,
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if a.options.baseURL == "" {
+		return nil, errorbank.NewValidationError("base_url", "Azure OpenAI resource endpoint is required", "")
+	}
+	if a.options.deployment == "" {
+		return nil, errorbank.NewValidationError("deployment", "Azure OpenAI deployment is required", "")
+	}
+	if a.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Azure OpenAI API key is required", "")
+	}
+
+	if err := a.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	path := "/openai/deployments/" + url.PathEscape(a.options.deployment) +
+		"/chat/completions?api-version=" + url.QueryEscape(a.options.apiVersion)
+
+	resp, err := failsafe.RetryWithResult(ctx, a.retrier, func() (*httpx.Response, error) {
+		return a.post(path, request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := a.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
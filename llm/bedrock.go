@@ -0,0 +1,353 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/pkg/jsoncodec"
+	"github.com/bpradana/tars/pkg/sigv4"
+	"github.com/bpradana/tars/template"
+)
+
+// BedrockProvider implements the BaseProvider interface for AWS Bedrock
+type BedrockProvider struct {
+	baseProvider
+}
+
+// NewBedrock creates a new AWS Bedrock provider using the Converse API.
+// Requests are signed with the credentials set by WithAWSCredentials. The
+// endpoint is derived from the region unless WithBaseURL is given, e.g. for
+// a VPC interface endpoint.
+func NewBedrock(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		region:       "us-east-1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.baseURL == "" {
+		opts.baseURL = "https://bedrock-runtime." + opts.region + ".amazonaws.com"
+	}
+
+	return &BedrockProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (b *BedrockProvider) GetName() string {
+	return "bedrock"
+}
+
+// Invoke implements the BaseProvider interface for Bedrock
+func (b *BedrockProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := bedrockParts.check("Bedrock", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "anthropic.claude-3-5-sonnet-20240620-v1:0",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	if err := validateShape(bedrockShape, template, opts); err != nil {
+		return nil, err
+	}
+
+	request := newBedrockRequest(template, opts)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if b.options.awsKeyID == "" || b.options.awsSecret == "" {
+		return nil, errorbank.NewValidationError("aws_credentials", "AWS credentials are required", "")
+	}
+
+	if err := b.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	body, err := jsoncodec.Marshal(request)
+	if err != nil {
+		return nil, errorbank.NewMessageError("json_marshal", "failed to marshal request", err)
+	}
+
+	// Model IDs contain colons, which Bedrock expects percent-encoded.
+	path := "/model/" + strings.ReplaceAll(url.PathEscape(opts.model), ":", "%3A") + "/converse"
+
+	resp, err := failsafe.RetryWithResult(ctx, b.retrier, func() (*httpx.Response, error) {
+		return b.signedPost(path, body)
+	})
+	if err != nil {
+		return nil, errorbank.NewMessageError("http_request", "failed to create request", err)
+	}
+	defer resp.Body.Close()
+
+	if err := b.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result bedrockResponse
+	if err := resp.Decode(&result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	content := result.text()
+	if content == "" {
+		return nil, errorbank.NewMessageError("no_choices", "no content in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		content,
+		message.WithUsage(
+			result.Usage.InputTokens,
+			result.Usage.OutputTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
+
+// signedPost sends a SigV4-signed POST request with a JSON body.
+// Each attempt is signed again so retries carry a fresh timestamp.
+func (b *BedrockProvider) signedPost(path string, body []byte) (*httpx.Response, error) {
+	req, err := b.client.POST(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req.WithHeader("Content-Type", "application/json").
+		WithBody(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	sigv4.Sign(req.Request, body, sigv4.Credentials{
+		AccessKeyID:     b.options.awsKeyID,
+		SecretAccessKey: b.options.awsSecret,
+		SessionToken:    b.options.awsToken,
+	}, b.options.region, "bedrock", time.Now())
+
+	return req.Do()
+}
+
+// bedrockDocument is a document attached to a Converse message.
+type bedrockDocument struct {
+	Format string `json:"format"`
+	Name   string `json:"name"`
+	Source struct {
+		Bytes []byte `json:"bytes"`
+	} `json:"source"`
+}
+
+// bedrockImage is an image attached to a Converse message.
+type bedrockImage struct {
+	Format string `json:"format"`
+	Source struct {
+		Bytes []byte `json:"bytes"`
+	} `json:"source"`
+}
+
+// bedrockToolUse is a tool call made by the model.
+type bedrockToolUse struct {
+	ToolUseID string          `json:"toolUseId"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+}
+
+// bedrockContentBlock is an element of a Converse message content array.
+type bedrockContentBlock struct {
+	Text     string           `json:"text,omitempty"`
+	Document *bedrockDocument `json:"document,omitempty"`
+	Image    *bedrockImage    `json:"image,omitempty"`
+	ToolUse  *bedrockToolUse  `json:"toolUse,omitempty"`
+}
+
+// bedrockMessage is a message in Converse format.
+type bedrockMessage struct {
+	Role    string                `json:"role"`
+	Content []bedrockContentBlock `json:"content"`
+}
+
+// bedrockInferenceConfig controls sampling.
+type bedrockInferenceConfig struct {
+	MaxTokens   int     `json:"maxTokens,omitempty"`
+	Temperature float64 `json:"temperature"`
+}
+
+// bedrockToolSpec describes a tool the model may call.
+type bedrockToolSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema struct {
+		JSON map[string]any `json:"json"`
+	} `json:"inputSchema"`
+}
+
+// bedrockToolConfig lists the available tools and how the model must use them.
+type bedrockToolConfig struct {
+	Tools []struct {
+		ToolSpec bedrockToolSpec `json:"toolSpec"`
+	} `json:"tools"`
+	ToolChoice struct {
+		Tool struct {
+			Name string `json:"name"`
+		} `json:"tool"`
+	} `json:"toolChoice"`
+}
+
+// bedrockRequest is the body of a Converse request.
+type bedrockRequest struct {
+	Messages        []bedrockMessage       `json:"messages"`
+	System          []bedrockContentBlock  `json:"system,omitempty"`
+	InferenceConfig bedrockInferenceConfig `json:"inferenceConfig"`
+	ToolConfig      *bedrockToolConfig     `json:"toolConfig,omitempty"`
+}
+
+// bedrockResponse is the body of a Converse response.
+type bedrockResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	StopReason string `json:"stopReason"`
+	Usage      struct {
+		InputTokens  int `json:"inputTokens"`
+		OutputTokens int `json:"outputTokens"`
+		TotalTokens  int `json:"totalTokens"`
+	} `json:"usage"`
+}
+
+// text returns the structured output tool input if present, otherwise the
+// concatenated text blocks of the response.
+func (r bedrockResponse) text() string {
+	var content strings.Builder
+	for _, block := range r.Output.Message.Content {
+		if block.ToolUse != nil && block.ToolUse.Name == anthropicSchemaTool {
+			return string(block.ToolUse.Input)
+		}
+		content.WriteString(block.Text)
+	}
+	return content.String()
+}
+
+// newBedrockRequest converts a template into a Converse request. System
+// messages are moved to the system field and structured output is obtained
+// by forcing a call to a tool whose input schema is the requested schema.
+func newBedrockRequest(template template.Template, opts invokeOptions) bedrockRequest {
+	request := bedrockRequest{
+		InferenceConfig: bedrockInferenceConfig{
+			MaxTokens:   opts.maxTokens,
+			Temperature: opts.temperature,
+		},
+	}
+
+	for _, msg := range opts.messages(template) {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+
+		if msg.GetRole() == message.RoleSystem {
+			request.System = append(request.System, bedrockContentBlock{Text: content})
+			continue
+		}
+
+		var blocks []bedrockContentBlock
+		for _, part := range msg.GetParts() {
+			switch part.Type {
+			case message.PartDocument:
+				document := &bedrockDocument{Format: part.Format, Name: bedrockDocumentName(part.Name)}
+				document.Source.Bytes = part.Data
+				blocks = append(blocks, bedrockContentBlock{Document: document})
+			case message.PartImage:
+				image := &bedrockImage{Format: part.Format}
+				image.Source.Bytes = part.Data
+				blocks = append(blocks, bedrockContentBlock{Image: image})
+			}
+		}
+		if content != "" {
+			blocks = append(blocks, bedrockContentBlock{Text: content})
+		}
+
+		request.Messages = append(request.Messages, bedrockMessage{
+			Role:    string(msg.GetRole()),
+			Content: blocks,
+		})
+	}
+
+	if opts.jsonSchema != nil {
+		spec := bedrockToolSpec{
+			Name:        anthropicSchemaTool,
+			Description: "Respond with output that matches this schema.",
+		}
+		spec.InputSchema.JSON = opts.jsonSchema
+
+		config := &bedrockToolConfig{}
+		config.Tools = append(config.Tools, struct {
+			ToolSpec bedrockToolSpec `json:"toolSpec"`
+		}{ToolSpec: spec})
+		config.ToolChoice.Tool.Name = anthropicSchemaTool
+		request.ToolConfig = config
+	}
+
+	return request
+}
+
+// bedrockDocumentName returns a document name accepted by Bedrock, which
+// only allows alphanumerics, whitespace, hyphens, parentheses, and brackets.
+func bedrockDocumentName(name string) string {
+	name = strings.TrimSuffix(name, ".pdf")
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == ' ', r == '-', r == '(', r == ')', r == '[', r == ']':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+	if name == "" {
+		return "document"
+	}
+	return name
+}
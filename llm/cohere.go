@@ -0,0 +1,211 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// CohereProvider implements the BaseProvider interface for Cohere
+type CohereProvider struct {
+	baseProvider
+}
+
+// NewCohere creates a new Cohere provider using the Chat API v2
+func NewCohere(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.cohere.com",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &CohereProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (c *CohereProvider) GetName() string {
+	return "cohere"
+}
+
+// Invoke implements the BaseProvider interface for Cohere
+func (c *CohereProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := textOnlyParts.check("Cohere", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "command-a-03-2025",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := newCohereRequest(template, opts)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if c.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Cohere API key is required", "")
+	}
+
+	if err := c.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, c.retrier, func() (*httpx.Response, error) {
+		return c.post("/v2/chat", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := c.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result cohereResponse
+	if err := resp.Decode(&result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	var content strings.Builder
+	for _, block := range result.Message.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no content in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(content.String(), opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Billed units are what the account is charged for, which is what
+	// usage tracking and budgets care about.
+	billed := result.Usage.BilledUnits
+	return message.FromAssistant(
+		content.String(),
+		message.WithUsage(
+			billed.InputTokens,
+			billed.OutputTokens,
+			billed.InputTokens+billed.OutputTokens,
+		),
+	), nil
+}
+
+// cohereMessage is a message in Cohere's Chat API v2 format.
+type cohereMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// cohereResponseFormat requests JSON output, optionally constrained by a schema.
+type cohereResponseFormat struct {
+	Type       string         `json:"type"`
+	JsonSchema map[string]any `json:"json_schema,omitempty"`
+}
+
+// cohereRequest is the body of a Chat API v2 request.
+type cohereRequest struct {
+	Model          string                `json:"model"`
+	Messages       []cohereMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *cohereResponseFormat `json:"response_format,omitempty"`
+}
+
+// cohereTokens counts input and output tokens.
+type cohereTokens struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// cohereResponse is the body of a Chat API v2 response.
+type cohereResponse struct {
+	ID      string `json:"id"`
+	Message struct {
+		Role    string `json:"role"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"message"`
+	FinishReason string `json:"finish_reason"`
+	Usage        struct {
+		BilledUnits cohereTokens `json:"billed_units"`
+		Tokens      cohereTokens `json:"tokens"`
+	} `json:"usage"`
+}
+
+// newCohereRequest converts a template into a Chat API v2 request.
+// Cohere uses the same role names as tars, so messages map one to one.
+func newCohereRequest(template template.Template, opts invokeOptions) cohereRequest {
+	request := cohereRequest{
+		Model:       opts.model,
+		Temperature: opts.temperature,
+		MaxTokens:   opts.maxTokens,
+	}
+
+	for _, msg := range opts.messages(template) {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+		request.Messages = append(request.Messages, cohereMessage{
+			Role:    string(msg.GetRole()),
+			Content: content,
+		})
+	}
+
+	if opts.jsonSchema != nil {
+		request.ResponseFormat = &cohereResponseFormat{
+			Type:       "json_object",
+			JsonSchema: opts.jsonSchema,
+		}
+	}
+
+	return request
+}
@@ -38,6 +38,12 @@ type baseProvider struct {
 	options llmOptions
 	client  *httpx.Client
 	retrier *failsafe.Retrier
+	keys    *keyPool
+	health  healthState
+
+	// apiKeyHeader is the header carrying the raw API key for providers
+	// that do not use bearer authentication.
+	apiKeyHeader string
 }
 
 // GetName returns the provider name - to be overridden by each provider.
@@ -0,0 +1,148 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// DeepSeekProvider implements the BaseProvider interface for DeepSeek
+type DeepSeekProvider struct {
+	baseProvider
+}
+
+// NewDeepSeek creates a new DeepSeek provider.
+// Use WithModel("deepseek-reasoner") for the reasoning model; its chain of
+// thought is available from GetReasoning on the returned message.
+func NewDeepSeek(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.deepseek.com",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &DeepSeekProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (d *DeepSeekProvider) GetName() string {
+	return "deepseek"
+}
+
+// Invoke implements the BaseProvider interface for DeepSeek
+func (d *DeepSeekProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := textOnlyParts.check("DeepSeek", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "deepseek-chat",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+	}
+
+	// DeepSeek only supports JSON mode, so the schema is given to the
+	// model as an instruction instead.
+	if opts.jsonSchema != nil {
+		schema, _ := json.Marshal(opts.jsonSchema)
+		request.Messages = append([]Message{{
+			Role:    string(message.RoleSystem),
+			Content: "Respond only with a JSON object that matches this JSON schema:\n" + string(schema),
+		}}, request.Messages...)
+		request.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if d.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "DeepSeek API key is required", "")
+	}
+
+	if err := d.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, d.retrier, func() (*httpx.Response, error) {
+		return d.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := d.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+		message.WithReasoning(result.Choices[0].Message.ReasoningContent),
+	), nil
+}
@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/jsoncodec"
+	"github.com/bpradana/tars/template"
+)
+
+// messageOverheadTokens approximates the tokens chat formats add around
+// each message (role markers and separators).
+const messageOverheadTokens = 3
+
+// dryRun builds the synthetic response returned when WithDryRun is set.
+// The content is the JSON request body that would have been sent, and the
+// usage carries the estimated prompt tokens.
+func dryRun(request any, template template.Template, opts invokeOptions) (message.Message, error) {
+	body, err := jsoncodec.Marshal(request)
+	if err != nil {
+		return nil, errorbank.NewMessageError("dry_run", "failed to render request", err)
+	}
+
+	msgs := newMessages(template, opts)
+	defer putMessages(msgs)
+
+	promptTokens := 0
+	for _, msg := range msgs {
+		promptTokens += message.EstimateTokens(msg.Content) + messageOverheadTokens
+	}
+
+	return message.FromAssistant(
+		string(body),
+		message.WithUsage(promptTokens, 0, promptTokens),
+	), nil
+}
@@ -23,6 +23,54 @@ const (
 	// ProviderOllama represents the Ollama provider.
 	// Supports local LLM models like Llama, Mistral, etc.
 	ProviderOllama ProviderType = "ollama"
+
+	// ProviderGemini represents the Google Gemini provider.
+	// Supports models like Gemini 2.0 Flash, Gemini 1.5 Pro, etc.
+	ProviderGemini ProviderType = "gemini"
+
+	// ProviderAzureOpenAI represents OpenAI models hosted on Azure.
+	// Requests are routed to a named deployment of an Azure resource.
+	ProviderAzureOpenAI ProviderType = "azure-openai"
+
+	// ProviderBedrock represents models served by AWS Bedrock.
+	// Supports Claude, Llama, Mistral, and other Bedrock models via the Converse API.
+	ProviderBedrock ProviderType = "bedrock"
+
+	// ProviderMistral represents the Mistral AI provider.
+	// Supports models like Mistral Large, Mistral Small, Codestral, etc.
+	ProviderMistral ProviderType = "mistral"
+
+	// ProviderGroq represents the Groq provider.
+	// Serves open models like Llama 3.3 and Mixtral with very low latency.
+	ProviderGroq ProviderType = "groq"
+
+	// ProviderDeepSeek represents the DeepSeek provider.
+	// Supports deepseek-chat and the deepseek-reasoner reasoning model.
+	ProviderDeepSeek ProviderType = "deepseek"
+
+	// ProviderXAI represents the xAI provider.
+	// Supports Grok models like Grok 3 and Grok 3 Mini.
+	ProviderXAI ProviderType = "xai"
+
+	// ProviderTogether represents the Together AI provider.
+	// Serves open-weight models like Llama, Qwen, DeepSeek, and Mixtral.
+	ProviderTogether ProviderType = "together"
+
+	// ProviderCohere represents the Cohere provider.
+	// Supports Command A, Command R+, and other Command models.
+	ProviderCohere ProviderType = "cohere"
+
+	// ProviderFireworks represents the Fireworks AI provider.
+	// Serves open models with JSON mode and grammar-constrained output.
+	ProviderFireworks ProviderType = "fireworks"
+
+	// ProviderHuggingFace represents the Hugging Face provider.
+	// Supports the serverless Inference API and dedicated Inference Endpoints.
+	ProviderHuggingFace ProviderType = "huggingface"
+
+	// ProviderLiteLLM represents a LiteLLM proxy.
+	// Serves any model configured on the proxy, with spend tracking metadata.
+	ProviderLiteLLM ProviderType = "litellm"
 )
 
 // NewProvider creates a new LLM provider based on the provider type.
@@ -51,6 +99,30 @@ func NewProvider(providerType ProviderType, options ...LLMOption) (BaseProvider,
 		return NewOpenRouter(options...), nil
 	case ProviderOllama:
 		return NewOllama(options...), nil
+	case ProviderGemini:
+		return NewGemini(options...), nil
+	case ProviderAzureOpenAI:
+		return NewAzureOpenAI(options...), nil
+	case ProviderBedrock:
+		return NewBedrock(options...), nil
+	case ProviderMistral:
+		return NewMistral(options...), nil
+	case ProviderGroq:
+		return NewGroq(options...), nil
+	case ProviderDeepSeek:
+		return NewDeepSeek(options...), nil
+	case ProviderXAI:
+		return NewXAI(options...), nil
+	case ProviderTogether:
+		return NewTogether(options...), nil
+	case ProviderCohere:
+		return NewCohere(options...), nil
+	case ProviderFireworks:
+		return NewFireworks(options...), nil
+	case ProviderHuggingFace:
+		return NewHuggingFace(options...), nil
+	case ProviderLiteLLM:
+		return NewLiteLLM(options...), nil
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
 	}
@@ -71,5 +143,17 @@ func GetSupportedProviders() []ProviderType {
 		ProviderAnthropic,
 		ProviderOpenRouter,
 		ProviderOllama,
+		ProviderGemini,
+		ProviderAzureOpenAI,
+		ProviderBedrock,
+		ProviderMistral,
+		ProviderGroq,
+		ProviderDeepSeek,
+		ProviderXAI,
+		ProviderTogether,
+		ProviderCohere,
+		ProviderFireworks,
+		ProviderHuggingFace,
+		ProviderLiteLLM,
 	}
 }
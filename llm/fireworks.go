@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// FireworksProvider implements the BaseProvider interface for Fireworks AI
+type FireworksProvider struct {
+	baseProvider
+}
+
+// NewFireworks creates a new Fireworks AI provider using its OpenAI-compatible API
+func NewFireworks(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.fireworks.ai/inference/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &FireworksProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// fireworksResponseFormat constrains the output to JSON, optionally matching
+// a schema, or to a GBNF grammar.
+type fireworksResponseFormat struct {
+	Type    string         `json:"type"`
+	Schema  map[string]any `json:"schema,omitempty"`
+	Grammar string         `json:"grammar,omitempty"`
+}
+
+// fireworksRequest is the body of a Fireworks chat completions request.
+type fireworksRequest struct {
+	Model          string                   `json:"model"`
+	Messages       []Message                `json:"messages"`
+	Temperature    float64                  `json:"temperature"`
+	MaxTokens      int                      `json:"max_tokens,omitempty"`
+	ResponseFormat *fireworksResponseFormat `json:"response_format,omitempty"`
+}
+
+// GetName returns the provider name
+func (f *FireworksProvider) GetName() string {
+	return "fireworks"
+}
+
+// Invoke implements the BaseProvider interface for Fireworks AI
+func (f *FireworksProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := visionParts.check("Fireworks", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "accounts/fireworks/models/llama-v3p3-70b-instruct",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := fireworksRequest{
+		Model:       opts.model,
+		Messages:    newMessages(template, opts),
+		Temperature: opts.temperature,
+		MaxTokens:   opts.maxTokens,
+	}
+
+	switch {
+	case opts.grammar != "":
+		request.ResponseFormat = &fireworksResponseFormat{Type: "grammar", Grammar: opts.grammar}
+	case opts.jsonSchema != nil:
+		request.ResponseFormat = &fireworksResponseFormat{Type: "json_object", Schema: opts.jsonSchema}
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if f.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Fireworks API key is required", "")
+	}
+
+	if err := f.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, f.retrier, func() (*httpx.Response, error) {
+		return f.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := f.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil && opts.grammar == "" {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
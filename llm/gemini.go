@@ -0,0 +1,325 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// GeminiProvider implements the BaseProvider interface for Google Gemini
+type GeminiProvider struct {
+	baseProvider
+}
+
+// NewGemini creates a new Gemini provider using the Generative Language API
+func NewGemini(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://generativelanguage.googleapis.com/v1beta",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &GeminiProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Set("x-goog-api-key", opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys:         newKeyPool(opts.apiKeys, opts.keyStrategy),
+			apiKeyHeader: "x-goog-api-key",
+		},
+	}
+}
+
+// GetName returns the provider name
+func (g *GeminiProvider) GetName() string {
+	return "gemini"
+}
+
+// Invoke implements the BaseProvider interface for Gemini
+func (g *GeminiProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := geminiParts.check("Gemini", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "gemini-2.0-flash",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	if err := validateShape(geminiShape, template, opts); err != nil {
+		return nil, err
+	}
+
+	request := newGeminiRequest(template, opts)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if g.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Gemini API key is required", "")
+	}
+
+	if err := g.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, g.retrier, func() (*httpx.Response, error) {
+		return g.post("/models/"+opts.model+":generateContent", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := g.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result geminiResponse
+	if err := resp.Decode(&result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Candidates) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no candidates in response", nil)
+	}
+
+	var content strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		content.WriteString(part.Text)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(content.String(), opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		content.String(),
+		message.WithUsage(
+			result.UsageMetadata.PromptTokenCount,
+			result.UsageMetadata.CandidatesTokenCount,
+			result.UsageMetadata.TotalTokenCount,
+		),
+	), nil
+}
+
+// geminiInlineData is base64-encoded binary content such as audio, images, or PDFs.
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+// geminiPart is an element of a Gemini content.
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+}
+
+// geminiContent is a turn of the conversation.
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+// geminiGenerationConfig controls sampling and the output format.
+type geminiGenerationConfig struct {
+	Temperature      float64        `json:"temperature"`
+	MaxOutputTokens  int            `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType string         `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any `json:"responseSchema,omitempty"`
+}
+
+// geminiRequest is the body of a generateContent request.
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+// geminiResponse is the body of a generateContent response.
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+	ModelVersion string `json:"modelVersion"`
+}
+
+// geminiMimeTypes maps part formats to the MIME types Gemini expects.
+var geminiMimeTypes = map[string]string{
+	"wav":  "audio/wav",
+	"mp3":  "audio/mp3",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"ogg":  "audio/ogg",
+	"aiff": "audio/aiff",
+	"pdf":  "application/pdf",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+}
+
+// newGeminiRequest converts a template into a generateContent request.
+// System messages become the system instruction and assistant messages
+// are sent with Gemini's "model" role.
+func newGeminiRequest(template template.Template, opts invokeOptions) geminiRequest {
+	request := geminiRequest{
+		GenerationConfig: geminiGenerationConfig{
+			Temperature:     opts.temperature,
+			MaxOutputTokens: opts.maxTokens,
+		},
+	}
+
+	for _, msg := range opts.messages(template) {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+
+		if msg.GetRole() == message.RoleSystem {
+			if request.SystemInstruction == nil {
+				request.SystemInstruction = &geminiContent{}
+			}
+			request.SystemInstruction.Parts = append(request.SystemInstruction.Parts, geminiPart{Text: content})
+			continue
+		}
+
+		role := "user"
+		if msg.GetRole() == message.RoleAssistant {
+			role = "model"
+		}
+
+		var parts []geminiPart
+		if content != "" {
+			parts = append(parts, geminiPart{Text: content})
+		}
+		for _, part := range msg.GetParts() {
+			parts = append(parts, geminiPart{
+				InlineData: &geminiInlineData{
+					MimeType: geminiMimeTypes[part.Format],
+					Data:     base64.StdEncoding.EncodeToString(part.Data),
+				},
+			})
+		}
+
+		request.Contents = append(request.Contents, geminiContent{Role: role, Parts: parts})
+	}
+
+	if opts.jsonSchema != nil {
+		request.GenerationConfig.ResponseMimeType = "application/json"
+		request.GenerationConfig.ResponseSchema = geminiSchema(inlineSchemaRefs(opts.jsonSchema))
+	}
+
+	return request
+}
+
+// geminiSchemaKeys lists the JSON Schema keywords supported by Gemini's
+// OpenAPI-based response schema. Other keywords are rejected by the API.
+var geminiSchemaKeys = map[string]bool{
+	"type":        true,
+	"format":      true,
+	"description": true,
+	"nullable":    true,
+	"enum":        true,
+	"properties":  true,
+	"required":    true,
+	"items":       true,
+	"minItems":    true,
+	"maxItems":    true,
+	"minimum":     true,
+	"maximum":     true,
+}
+
+// geminiSchema returns a copy of a JSON schema reduced to the keywords Gemini
+// supports. Nullable types, written as a type list or an anyOf with null,
+// are converted to Gemini's nullable keyword.
+func geminiSchema(schema map[string]any) map[string]any {
+	if options, ok := schema["anyOf"].([]any); ok && len(options) == 2 {
+		for i, option := range options {
+			if option, ok := option.(map[string]any); ok && option["type"] == "null" {
+				if other, ok := options[1-i].(map[string]any); ok {
+					result := geminiSchema(other)
+					result["nullable"] = true
+					return result
+				}
+			}
+		}
+	}
+
+	result := make(map[string]any, len(schema))
+	for key, value := range schema {
+		if !geminiSchemaKeys[key] {
+			continue
+		}
+
+		switch key {
+		case "properties":
+			if properties, ok := value.(map[string]any); ok {
+				converted := make(map[string]any, len(properties))
+				for name, property := range properties {
+					if property, ok := property.(map[string]any); ok {
+						converted[name] = geminiSchema(property)
+					}
+				}
+				value = converted
+			}
+		case "items":
+			if items, ok := value.(map[string]any); ok {
+				value = geminiSchema(items)
+			}
+		case "type":
+			if types, ok := value.([]any); ok {
+				for _, typ := range types {
+					if typ == "null" {
+						result["nullable"] = true
+					} else {
+						value = typ
+					}
+				}
+			}
+		}
+
+		result[key] = value
+	}
+	return result
+}
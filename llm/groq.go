@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// GroqProvider implements the BaseProvider interface for Groq
+type GroqProvider struct {
+	baseProvider
+	rateLimits rateLimitState
+}
+
+// NewGroq creates a new Groq provider using its OpenAI-compatible API
+func NewGroq(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.groq.com/openai/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &GroqProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (g *GroqProvider) GetName() string {
+	return "groq"
+}
+
+// RateLimits returns the rate limits reported by the last Groq response.
+func (g *GroqProvider) RateLimits() RateLimits {
+	return g.rateLimits.get()
+}
+
+// Invoke implements the BaseProvider interface for Groq
+func (g *GroqProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := visionParts.check("Groq", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "llama-3.3-70b-versatile",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if g.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Groq API key is required", "")
+	}
+
+	if err := g.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, g.retrier, func() (*httpx.Response, error) {
+		return g.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	// Groq enforces tight per-minute limits. When one is hit, cool off
+	// until the window resets so routers can fall back in the meantime.
+	limits := g.rateLimits.record(resp)
+	if cooloff := limits.cooloff(resp); cooloff > 0 && g.keys == nil {
+		g.markUnhealthy("rate limited", cooloff)
+	}
+
+	if err := g.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Health describes whether a provider is currently accepting requests.
+type Health struct {
+	// Healthy is false while the provider is cooling off.
+	Healthy bool
+
+	// UnhealthyUntil is the end of the current cool-off window.
+	UnhealthyUntil time.Time
+
+	// Reason explains why the provider was marked unhealthy.
+	Reason string
+}
+
+// HealthReporter is implemented by providers that track their own health.
+// All built-in providers implement it, which allows routers and fallbacks
+// to skip providers that are known to be unavailable.
+//
+// Example:
+//
+//	if reporter, ok := provider.(HealthReporter); ok && !reporter.Health().Healthy {
+//	  // pick another provider
+//	}
+type HealthReporter interface {
+	Health() Health
+}
+
+// Unwrapper is implemented by providers that wrap another provider, such as
+// guards and exporters, so the capabilities of the wrapped provider can
+// still be reached through the wrapper.
+type Unwrapper interface {
+	Unwrap() BaseProvider
+}
+
+// HealthOf returns the health of a provider, following Unwrap through any
+// wrappers until a HealthReporter is found. The boolean is false when no
+// provider in the chain reports its health.
+//
+// Example:
+//
+//	if health, ok := llm.HealthOf(provider); ok && !health.Healthy {
+//	  log.Printf("%s is cooling off: %s", provider.GetName(), health.Reason)
+//	}
+func HealthOf(provider BaseProvider) (Health, bool) {
+	for provider != nil {
+		if reporter, ok := provider.(HealthReporter); ok {
+			return reporter.Health(), true
+		}
+		unwrapper, ok := provider.(Unwrapper)
+		if !ok {
+			break
+		}
+		provider = unwrapper.Unwrap()
+	}
+	return Health{}, false
+}
+
+// healthState tracks the cool-off window of a provider.
+type healthState struct {
+	mu             sync.RWMutex
+	unhealthyUntil time.Time
+	reason         string
+}
+
+// Health returns the current health of the provider.
+func (b *baseProvider) Health() Health {
+	b.health.mu.RLock()
+	defer b.health.mu.RUnlock()
+
+	if time.Now().Before(b.health.unhealthyUntil) {
+		return Health{
+			Healthy:        false,
+			UnhealthyUntil: b.health.unhealthyUntil,
+			Reason:         b.health.reason,
+		}
+	}
+	return Health{Healthy: true}
+}
+
+// checkHealth returns an error if the provider is cooling off.
+func (b *baseProvider) checkHealth() error {
+	health := b.Health()
+	if !health.Healthy {
+		return errorbank.NewMessageError(
+			"provider_unavailable",
+			fmt.Sprintf("provider is cooling off until %s", health.UnhealthyUntil.Format(time.RFC3339)),
+			nil,
+		)
+	}
+	return nil
+}
+
+// markUnhealthy starts a cool-off window for the provider.
+func (b *baseProvider) markUnhealthy(reason string, cooloff time.Duration) {
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+
+	b.health.unhealthyUntil = time.Now().Add(cooloff)
+	b.health.reason = reason
+}
+
+// checkResponse converts error responses into errors. When the response
+// indicates that the quota or credits of the account are exhausted,
+// the provider is marked unhealthy for the configured cool-off window.
+// Providers with a key pool track exhaustion per key instead.
+func (b *baseProvider) checkResponse(resp *httpx.Response) error {
+	if !resp.IsError() {
+		return nil
+	}
+
+	if isQuotaExhausted(resp) {
+		if b.options.quotaCooloff > 0 && b.keys == nil {
+			b.markUnhealthy("quota exhausted", b.options.quotaCooloff)
+		}
+		return errorbank.NewMessageError("quota_exhausted", "provider quota or credits exhausted", resp.Error())
+	}
+
+	return errorbank.NewMessageError("http_status", "provider returned an error response", resp.Error())
+}
+
+// quotaExhaustedMarkers are error codes and messages that providers only
+// send when the quota or credits of the account are used up. Generic words
+// such as "quota" are avoided, since per-minute rate limits mention them
+// too (e.g. Gemini's "Quota exceeded for metric ... per minute").
+var quotaExhaustedMarkers = []string{
+	"insufficient_quota",
+	"billing_hard_limit_reached",
+	"billing_not_active",
+	"credit balance is too low",
+	"insufficient_credits",
+	"insufficient balance",
+}
+
+// isQuotaExhausted reports whether an error response signals exhausted quota
+// or credits, as opposed to a transient rate limit. Other 429 responses are
+// rate limits and do not take the provider offline.
+func isQuotaExhausted(resp *httpx.Response) bool {
+	switch resp.StatusCode() {
+	case http.StatusPaymentRequired:
+		return true
+	case http.StatusTooManyRequests, http.StatusForbidden, http.StatusBadRequest:
+		body := strings.ToLower(resp.String())
+		for _, marker := range quotaExhaustedMarkers {
+			if strings.Contains(body, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
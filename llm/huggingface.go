@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// HuggingFaceProvider implements the BaseProvider interface for Hugging Face
+type HuggingFaceProvider struct {
+	baseProvider
+}
+
+// NewHuggingFace creates a new Hugging Face provider. By default it targets
+// the serverless Inference API; set WithBaseURL to the /v1 URL of a
+// dedicated Inference Endpoint to use it instead, e.g.
+// https://my-endpoint.us-east-1.aws.endpoints.huggingface.cloud/v1.
+// Both expose the chat-completion-compatible route. Token usage is reported
+// when the serving backend provides it and is zero otherwise.
+func NewHuggingFace(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://router.huggingface.co/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &HuggingFaceProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (h *HuggingFaceProvider) GetName() string {
+	return "huggingface"
+}
+
+// Invoke implements the BaseProvider interface for Hugging Face
+func (h *HuggingFaceProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := textOnlyParts.check("Hugging Face", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "meta-llama/Llama-3.3-70B-Instruct",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+		ServiceTier: opts.serviceTier,
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if h.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Hugging Face access token is required", "")
+	}
+
+	if err := h.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, h.retrier, func() (*httpx.Response, error) {
+		return h.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := h.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
@@ -0,0 +1,286 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// KeyStrategy defines how a provider picks among multiple API keys.
+type KeyStrategy string
+
+const (
+	// KeyRoundRobin cycles through the keys in order, skipping keys
+	// that are currently rate limited.
+	KeyRoundRobin KeyStrategy = "round_robin"
+
+	// KeyLeastRecentlyThrottled prefers the key that was rate limited
+	// the longest time ago (or never), spreading load away from hot keys.
+	KeyLeastRecentlyThrottled KeyStrategy = "least_recently_throttled"
+)
+
+// defaultThrottleWindow is how long a key is avoided after a rate limit
+// response that does not carry a Retry-After header.
+const defaultThrottleWindow = 30 * time.Second
+
+// keyState tracks the rate-limit and quota state of a single API key.
+type keyState struct {
+	key            string
+	throttledUntil time.Time
+	lastThrottled  time.Time
+	exhaustedUntil time.Time
+}
+
+// availableAt returns when the key can be used again.
+func (s *keyState) availableAt() time.Time {
+	if s.exhaustedUntil.After(s.throttledUntil) {
+		return s.exhaustedUntil
+	}
+	return s.throttledUntil
+}
+
+// keyPool rotates requests across multiple API keys.
+type keyPool struct {
+	mu       sync.Mutex
+	strategy KeyStrategy
+	keys     []*keyState
+	cursor   int
+}
+
+// newKeyPool creates a key pool, or returns nil when there is nothing to rotate.
+func newKeyPool(keys []string, strategy KeyStrategy) *keyPool {
+	if len(keys) < 2 {
+		return nil
+	}
+
+	if strategy == "" {
+		strategy = KeyRoundRobin
+	}
+
+	states := make([]*keyState, len(keys))
+	for i, key := range keys {
+		states[i] = &keyState{key: key}
+	}
+
+	return &keyPool{
+		strategy: strategy,
+		keys:     states,
+	}
+}
+
+// next picks the key to use for the next request.
+// If every key is rate limited or out of quota, the one that becomes
+// available first is returned.
+func (p *keyPool) next() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var selected *keyState
+	selectedIndex := 0
+	for i := range p.keys {
+		index := (p.cursor + i) % len(p.keys)
+		state := p.keys[index]
+		if now.Before(state.availableAt()) {
+			continue
+		}
+
+		if selected == nil ||
+			(p.strategy == KeyLeastRecentlyThrottled && state.lastThrottled.Before(selected.lastThrottled)) {
+			selected = state
+			selectedIndex = index
+		}
+
+		if p.strategy == KeyRoundRobin {
+			break
+		}
+	}
+
+	if selected == nil {
+		for index, state := range p.keys {
+			if selected == nil || state.availableAt().Before(selected.availableAt()) {
+				selected = state
+				selectedIndex = index
+			}
+		}
+	}
+
+	p.cursor = (selectedIndex + 1) % len(p.keys)
+	return selected.key
+}
+
+// throttle marks a key as rate limited for the given duration.
+func (p *keyPool) throttle(key string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, state := range p.keys {
+		if state.key == key {
+			state.throttledUntil = now.Add(duration)
+			state.lastThrottled = now
+			return
+		}
+	}
+}
+
+// exhaust marks a key as out of quota for the given duration.
+func (p *keyPool) exhaust(key string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, state := range p.keys {
+		if state.key == key {
+			state.exhaustedUntil = time.Now().Add(duration)
+			return
+		}
+	}
+}
+
+// exhausted reports whether every key is out of quota, and if so when the
+// first of them regains it.
+func (p *keyPool) exhausted() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var first time.Time
+	for _, state := range p.keys {
+		if !now.Before(state.exhaustedUntil) {
+			return time.Time{}, false
+		}
+		if first.IsZero() || state.exhaustedUntil.Before(first) {
+			first = state.exhaustedUntil
+		}
+	}
+	return first, true
+}
+
+// size returns the number of keys in the pool.
+func (p *keyPool) size() int {
+	return len(p.keys)
+}
+
+// post sends a JSON POST request, rotating the API key when the provider
+// is configured with a key pool.
+func (b *baseProvider) post(path string, body any) (*httpx.Response, error) {
+	if b.keys == nil {
+		return b.client.Post(path, body)
+	}
+
+	return b.withKeys(func(key string) (*httpx.Response, error) {
+		req, err := b.client.POST(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.apiKeyHeader != "" {
+			req.WithHeader(b.apiKeyHeader, key)
+		} else {
+			req.WithHeader("Authorization", "Bearer "+key)
+		}
+		return req.WithJSON(body).Do()
+	})
+}
+
+// withKeys sends a request with the next key of the pool. When the key is
+// rate limited or out of quota, the rejection is recorded for that key and
+// the request moves on to the next one, trying each key at most once. If
+// every key rejects it, a keyPoolError carrying the last rejection is
+// returned so the retrier can try again later.
+func (b *baseProvider) withKeys(send func(key string) (*httpx.Response, error)) (*httpx.Response, error) {
+	var rejection error
+	tried := make(map[string]bool, b.keys.size())
+	for len(tried) < b.keys.size() {
+		key := b.keys.next()
+		if tried[key] {
+			break
+		}
+		tried[key] = true
+
+		resp, err := send(key)
+		if err != nil {
+			return nil, err
+		}
+
+		rejection = b.recordKey(key, resp)
+		if rejection == nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+	return nil, &keyPoolError{rejection: rejection}
+}
+
+// keyPoolError is returned by withKeys when every key of the pool rejected
+// a request. The rejection is a quota_exhausted or rate_limited error.
+type keyPoolError struct {
+	rejection error
+}
+
+// Error returns the error of the last rejection.
+func (e *keyPoolError) Error() string {
+	return e.rejection.Error()
+}
+
+// Unwrap returns the last rejection.
+func (e *keyPoolError) Unwrap() error {
+	return e.rejection
+}
+
+// requestError wraps an error from sending a request. When every key of
+// the pool rejected the request, the rejection is returned as is, so callers
+// see codes such as quota_exhausted instead of a generic request failure.
+func requestError(err error) error {
+	var poolErr *keyPoolError
+	if errors.As(err, &poolErr) {
+		return poolErr.rejection
+	}
+	return errorbank.NewMessageError("http_request", "failed to create request", err)
+}
+
+// recordKey records a rate limit or exhausted quota on the key that was
+// used for a response and returns it as an error. The provider is only
+// marked unhealthy once every key of the pool is out of quota.
+func (b *baseProvider) recordKey(key string, resp *httpx.Response) error {
+	switch {
+	case isQuotaExhausted(resp):
+		if b.options.quotaCooloff > 0 {
+			b.keys.exhaust(key, b.options.quotaCooloff)
+			if until, ok := b.keys.exhausted(); ok {
+				b.markUnhealthy("quota exhausted on every API key", time.Until(until))
+			}
+		}
+		return errorbank.NewMessageError("quota_exhausted", "API key quota or credits exhausted", resp.Error())
+	case resp.StatusCode() == http.StatusTooManyRequests:
+		b.keys.throttle(key, retryAfter(resp, defaultThrottleWindow))
+		return errorbank.NewMessageError("rate_limited", "API key is rate limited", resp.Error())
+	}
+	return nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a
+// response, or the fallback when the header is missing or malformed.
+func retryAfter(resp *httpx.Response, fallback time.Duration) time.Duration {
+	value := resp.GetHeader("Retry-After")
+	if value == "" {
+		return fallback
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,26 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+// applyDeadline downgrades the model and service tier when the remaining
+// time before the context deadline is shorter than the configured budget.
+func (opts *invokeOptions) applyDeadline(ctx context.Context) {
+	if opts.deadlineBudget <= 0 {
+		return
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) >= opts.deadlineBudget {
+		return
+	}
+
+	if opts.deadlineModel != "" {
+		opts.model = opts.deadlineModel
+	}
+	if opts.serviceTier == "flex" {
+		opts.serviceTier = "default"
+	}
+}
@@ -0,0 +1,160 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// LiteLLMProvider implements the BaseProvider interface for a LiteLLM proxy
+type LiteLLMProvider struct {
+	baseProvider
+}
+
+// NewLiteLLM creates a new provider for a LiteLLM proxy. The base URL is the
+// proxy address and models are the names configured on the proxy. The API
+// key is a LiteLLM virtual key and may be omitted for unauthenticated proxies.
+func NewLiteLLM(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "http://localhost:4000",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &LiteLLMProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// liteLLMRequest is a chat completions request with the fields LiteLLM uses
+// for spend tracking.
+type liteLLMRequest struct {
+	ChatCompletionsRequest
+	Temperature float64        `json:"temperature"`
+	MaxTokens   int            `json:"max_tokens,omitempty"`
+	User        string         `json:"user,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+// GetName returns the provider name
+func (l *LiteLLMProvider) GetName() string {
+	return "litellm"
+}
+
+// Invoke implements the BaseProvider interface for a LiteLLM proxy
+func (l *LiteLLMProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := openAIParts.check("LiteLLM", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "gpt-4o-mini",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := liteLLMRequest{
+		Temperature: opts.temperature,
+		MaxTokens:   opts.maxTokens,
+		User:        opts.user,
+		Metadata:    opts.metadata,
+	}
+	request.ChatCompletionsRequest = ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+		ServiceTier: opts.serviceTier,
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	if err := l.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, l.retrier, func() (*httpx.Response, error) {
+		return l.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := l.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
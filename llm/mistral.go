@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// MistralProvider implements the BaseProvider interface for Mistral AI
+type MistralProvider struct {
+	baseProvider
+}
+
+// NewMistral creates a new Mistral AI provider for La Plateforme
+func NewMistral(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.mistral.ai/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &MistralProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// GetName returns the provider name
+func (m *MistralProvider) GetName() string {
+	return "mistral"
+}
+
+// Invoke implements the BaseProvider interface for Mistral AI
+func (m *MistralProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := mistralParts.check("Mistral", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{
+		model:       "mistral-small-latest",
+		temperature: 0.7,
+		maxTokens:   1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if m.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Mistral API key is required", "")
+	}
+
+	if err := m.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, m.retrier, func() (*httpx.Response, error) {
+		return m.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := m.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Moderation is the outcome of checking text against a content policy.
+type Moderation struct {
+	// Flagged is true when the text violates any category.
+	Flagged bool
+
+	// Categories reports, per category such as "harassment" or
+	// "self-harm/intent", whether the text violates it.
+	Categories map[string]bool
+
+	// Scores is the model's confidence per category, between 0 and 1.
+	Scores map[string]float64
+}
+
+// FlaggedCategories returns the violated categories, sorted by name.
+func (m Moderation) FlaggedCategories() []string {
+	var categories []string
+	for category, flagged := range m.Categories {
+		if flagged {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Moderator is implemented by providers that classify text against a
+// content policy.
+type Moderator interface {
+	Moderate(ctx context.Context, text string, options ...InvokeOption) (Moderation, error)
+}
+
+// openAIModerationRequest is the request of the OpenAI moderations endpoint.
+type openAIModerationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// openAIModerationResponse is the response of the OpenAI moderations endpoint.
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// Moderate implements the Moderator interface using OpenAI's moderation
+// endpoint. Only WithModel applies; the default model is
+// omni-moderation-latest.
+//
+// Example:
+//
+//	moderation, err := provider.(llm.Moderator).Moderate(ctx, userInput)
+//	if moderation.Flagged {
+//	  log.Printf("rejected: %v", moderation.FlaggedCategories())
+//	}
+func (o *OpenAIProvider) Moderate(ctx context.Context, text string, options ...InvokeOption) (Moderation, error) {
+	opts := invokeOptions{
+		model: "omni-moderation-latest",
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if o.options.apiKey == "" {
+		return Moderation{}, errorbank.NewValidationError("api_key", "OpenAI API key is required", "")
+	}
+
+	if err := o.checkHealth(); err != nil {
+		return Moderation{}, err
+	}
+
+	request := openAIModerationRequest{Model: opts.model, Input: text}
+	resp, err := failsafe.RetryWithResult(ctx, o.retrier, func() (*httpx.Response, error) {
+		return o.post("/moderations", request)
+	})
+	if err != nil {
+		return Moderation{}, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := o.checkResponse(resp); err != nil {
+		return Moderation{}, err
+	}
+
+	var result openAIModerationResponse
+	if err := resp.Decode(&result); err != nil {
+		return Moderation{}, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Results) == 0 {
+		return Moderation{}, errorbank.NewMessageError("no_results", "no results in response", nil)
+	}
+
+	return Moderation{
+		Flagged:    result.Results[0].Flagged,
+		Categories: result.Results[0].Categories,
+		Scores:     result.Results[0].CategoryScores,
+	}, nil
+}
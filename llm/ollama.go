@@ -2,7 +2,7 @@ package llm
 
 import (
 	"context"
-	"encoding/json"
+	"sync/atomic"
 	"time"
 
 	"github.com/bpradana/failsafe"
@@ -16,15 +16,17 @@ import (
 // OllamaProvider implements the BaseProvider interface for Ollama
 type OllamaProvider struct {
 	baseProvider
+	lastUsed atomic.Int64
 }
 
 // NewOllama creates a new Ollama provider
 func NewOllama(options ...LLMOption) BaseProvider {
 	opts := llmOptions{
-		baseURL:     "http://localhost:11434",
-		timeout:     10 * time.Second,
-		maxAttempts: 1,
-		maxDelay:    0 * time.Second,
+		baseURL:      "http://localhost:11434",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
 	}
 
 	for _, option := range options {
@@ -36,7 +38,8 @@ func NewOllama(options ...LLMOption) BaseProvider {
 			options: opts,
 			client: httpx.NewClient().
 				WithBaseURL(opts.baseURL).
-				WithTimeout(opts.timeout),
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
 			retrier: failsafe.NewRetrier(
 				failsafe.WithMaxAttempts(opts.maxAttempts),
 				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
@@ -56,6 +59,9 @@ func (o *OllamaProvider) Invoke(ctx context.Context, template template.Template,
 	if err := template.Validate(); err != nil {
 		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
 	}
+	if err := textOnlyParts.check("Ollama", template); err != nil {
+		return nil, err
+	}
 
 	opts := invokeOptions{
 		model:       "llama3.1:8b",
@@ -66,62 +72,275 @@ func (o *OllamaProvider) Invoke(ctx context.Context, template template.Template,
 		option(&opts)
 	}
 
+	opts.applyDeadline(ctx)
+
+	request := o.newChatRequest(template, opts)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	if err := o.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	o.lastUsed.Store(time.Now().UnixNano())
+
 	resp, err := failsafe.RetryWithResult(ctx, o.retrier, func() (*httpx.Response, error) {
-		return o.client.Post("/chat", ChatCompletionsRequest{
-			Model: opts.model,
-			Messages: func() []Message {
-				templateMessages := template.GetMessage()
-				msgs := make([]Message, len(templateMessages))
-				for i, msg := range templateMessages {
-					msgs[i] = Message{
-						Role:    string(msg.GetRole()),
-						Content: msg.GetContent(),
-					}
-				}
-				return msgs
-			}(),
-			ResponseFormat: func() *ResponseFormat {
-				if opts.jsonSchema != nil {
-					return &ResponseFormat{
-						Type: "json_schema",
-						JsonSchema: JsonSchema{
-							Name:   "schema",
-							Strict: true,
-							Schema: opts.jsonSchema,
-						},
-					}
-				}
-				return nil
-			}(),
-		})
+		return o.post("/api/chat", request)
 	})
 	if err != nil {
-		return nil, errorbank.NewMessageError("http_request", "failed to create request", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
-	var result ChatCompletionsResponse
+	if err := o.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result ollamaChatResponse
 	if err := resp.Decode(&result); err != nil {
 		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
 	}
 
-	if len(result.Choices) == 0 {
-		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	if result.Message.Content == "" {
+		return nil, errorbank.NewMessageError("no_choices", "no content in response", nil)
 	}
 
 	if opts.jsonSchema != nil {
-		err = json.Unmarshal([]byte(result.Choices[0].Message.Content), opts.structuredOutput)
-		if err != nil {
-			return nil, errorbank.NewMessageError("json_unmarshal", "failed to unmarshal structured output", err)
+		if err := decodeStructuredOutput(result.Message.Content, opts); err != nil {
+			return nil, err
 		}
 	}
 
 	return message.FromAssistant(
-		result.Choices[0].Message.Content,
+		result.Message.Content,
 		message.WithUsage(
-			result.Usage.PromptTokens,
-			result.Usage.CompletionTokens,
-			result.Usage.TotalTokens,
+			result.PromptEvalCount,
+			result.EvalCount,
+			result.PromptEvalCount+result.EvalCount,
 		),
 	), nil
 }
+
+// ollamaMessage is a message in Ollama's native chat format.
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaChatOptions holds the model parameters of a native chat request.
+// NumGPU is a pointer because zero layers is a meaningful value.
+type ollamaChatOptions struct {
+	Temperature   float64 `json:"temperature"`
+	NumPredict    int     `json:"num_predict,omitempty"`
+	NumCtx        int     `json:"num_ctx,omitempty"`
+	NumGPU        *int    `json:"num_gpu,omitempty"`
+	RepeatPenalty float64 `json:"repeat_penalty,omitempty"`
+	Mirostat      int     `json:"mirostat,omitempty"`
+	MirostatTau   float64 `json:"mirostat_tau,omitempty"`
+	MirostatEta   float64 `json:"mirostat_eta,omitempty"`
+}
+
+// ollamaChatRequest is the body of Ollama's /api/chat endpoint.
+// Format is a JSON schema that constrains the output when set.
+type ollamaChatRequest struct {
+	Model     string            `json:"model"`
+	Messages  []ollamaMessage   `json:"messages"`
+	Stream    bool              `json:"stream"`
+	Format    map[string]any    `json:"format,omitempty"`
+	Options   ollamaChatOptions `json:"options"`
+	KeepAlive string            `json:"keep_alive,omitempty"`
+}
+
+// ollamaChatResponse is the body of a non-streaming /api/chat response.
+// Token counts are reported as prompt_eval_count and eval_count.
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+// newChatRequest converts a template into a native /api/chat request.
+func (o *OllamaProvider) newChatRequest(template template.Template, opts invokeOptions) ollamaChatRequest {
+	request := ollamaChatRequest{
+		Model:  opts.model,
+		Format: opts.jsonSchema,
+		Options: ollamaChatOptions{
+			Temperature:   opts.temperature,
+			NumPredict:    opts.maxTokens,
+			NumCtx:        opts.contextWindow,
+			NumGPU:        opts.gpuLayers,
+			RepeatPenalty: opts.repeatPenalty,
+			Mirostat:      opts.mirostat,
+			MirostatTau:   opts.mirostatTau,
+			MirostatEta:   opts.mirostatEta,
+		},
+	}
+	switch {
+	case opts.keepAlive != nil:
+		request.KeepAlive = opts.keepAlive.String()
+	case o.options.keepAlive != 0:
+		request.KeepAlive = o.options.keepAlive.String()
+	}
+
+	for _, msg := range opts.messages(template) {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+		request.Messages = append(request.Messages, ollamaMessage{
+			Role:    string(msg.GetRole()),
+			Content: content,
+		})
+	}
+
+	return request
+}
+
+// ollamaGenerateRequest is the body of Ollama's /api/generate endpoint.
+// A request without a prompt only loads the model into memory.
+type ollamaGenerateRequest struct {
+	Model     string `json:"model"`
+	KeepAlive string `json:"keep_alive,omitempty"`
+}
+
+// WarmUp loads the model into memory so the first real request does not pay
+// the cold-start latency. The model stays loaded for the duration set with
+// WithKeepAlive, or Ollama's default when it is not set.
+//
+// Example:
+//
+//	provider := NewOllama(WithKeepAlive(30 * time.Minute)).(*OllamaProvider)
+//	if err := provider.WarmUp(ctx, "llama3.1:8b"); err != nil {
+//	  log.Printf("warm-up failed: %v", err)
+//	}
+func (o *OllamaProvider) WarmUp(ctx context.Context, model string) error {
+	request := ollamaGenerateRequest{Model: model}
+	if o.options.keepAlive != 0 {
+		request.KeepAlive = o.options.keepAlive.String()
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, o.retrier, func() (*httpx.Response, error) {
+		return o.client.Post("/api/generate", request)
+	})
+	if err != nil {
+		return errorbank.NewMessageError("http_request", "failed to create request", err)
+	}
+	defer resp.Body.Close()
+
+	if err := o.checkResponse(resp); err != nil {
+		return err
+	}
+
+	o.lastUsed.Store(time.Now().UnixNano())
+	return nil
+}
+
+// defaultKeepWarmInterval is used by KeepWarm for non-positive intervals.
+// It is just under Ollama's default keep-alive of five minutes.
+const defaultKeepWarmInterval = 4 * time.Minute
+
+// KeepWarm keeps the model loaded by calling WarmUp whenever the provider
+// has been idle for the given interval. It blocks until the context is
+// cancelled, so it is usually started in its own goroutine. A non-positive
+// interval uses 4 minutes, just under Ollama's default keep-alive.
+//
+// Example:
+//
+//	go provider.KeepWarm(ctx, "llama3.1:8b", 4*time.Minute)
+func (o *OllamaProvider) KeepWarm(ctx context.Context, model string, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultKeepWarmInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(time.Unix(0, o.lastUsed.Load())) >= interval {
+			_ = o.WarmUp(ctx, model)
+		}
+	}
+}
+
+// OllamaModelDetails describes the format and size of a model.
+type OllamaModelDetails struct {
+	ParentModel       string   `json:"parent_model"`
+	Format            string   `json:"format"`
+	Family            string   `json:"family"`
+	Families          []string `json:"families"`
+	ParameterSize     string   `json:"parameter_size"`
+	QuantizationLevel string   `json:"quantization_level"`
+}
+
+// OllamaRunningModel describes a model currently loaded by Ollama.
+type OllamaRunningModel struct {
+	Name      string             `json:"name"`
+	Model     string             `json:"model"`
+	Size      int64              `json:"size"`
+	SizeVRAM  int64              `json:"size_vram"`
+	Digest    string             `json:"digest"`
+	Details   OllamaModelDetails `json:"details"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+// RunningModels returns the models currently loaded by Ollama, including
+// their total and VRAM memory usage (Ollama's /api/ps endpoint).
+//
+// Example:
+//
+//	models, err := provider.RunningModels(ctx)
+//	for _, model := range models {
+//	  fmt.Printf("%s: %d MiB VRAM\n", model.Name, model.SizeVRAM>>20)
+//	}
+func (o *OllamaProvider) RunningModels(ctx context.Context) ([]OllamaRunningModel, error) {
+	var result struct {
+		Models []OllamaRunningModel `json:"models"`
+	}
+	if err := o.get(ctx, "/api/ps", &result); err != nil {
+		return nil, err
+	}
+	return result.Models, nil
+}
+
+// Version returns the version of the Ollama server (Ollama's /api/version endpoint).
+func (o *OllamaProvider) Version(ctx context.Context) (string, error) {
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := o.get(ctx, "/api/version", &result); err != nil {
+		return "", err
+	}
+	return result.Version, nil
+}
+
+// get performs a GET request against the Ollama API and decodes the response.
+func (o *OllamaProvider) get(ctx context.Context, path string, v any) error {
+	resp, err := failsafe.RetryWithResult(ctx, o.retrier, func() (*httpx.Response, error) {
+		return o.client.Get(path)
+	})
+	if err != nil {
+		return errorbank.NewMessageError("http_request", "failed to create request", err)
+	}
+	defer resp.Body.Close()
+
+	if err := o.checkResponse(resp); err != nil {
+		return err
+	}
+
+	if err := resp.Decode(v); err != nil {
+		return errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+	return nil
+}
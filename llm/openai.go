@@ -2,7 +2,6 @@ package llm
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/bpradana/failsafe"
@@ -21,10 +20,11 @@ type OpenAIProvider struct {
 // NewOpenAI creates a new OpenAI provider
 func NewOpenAI(options ...LLMOption) BaseProvider {
 	opts := llmOptions{
-		baseURL:     "https://api.openai.com/v1",
-		timeout:     10 * time.Second,
-		maxAttempts: 1,
-		maxDelay:    0 * time.Second,
+		baseURL:      "https://api.openai.com/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
 	}
 
 	for _, option := range options {
@@ -37,11 +37,13 @@ func NewOpenAI(options ...LLMOption) BaseProvider {
 			client: httpx.NewClient().
 				WithBaseURL(opts.baseURL).
 				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
-				WithTimeout(opts.timeout),
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
 			retrier: failsafe.NewRetrier(
 				failsafe.WithMaxAttempts(opts.maxAttempts),
 				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
 			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
 		},
 	}
 }
@@ -57,6 +59,9 @@ func (o *OpenAIProvider) Invoke(ctx context.Context, template template.Template,
 	if err := template.Validate(); err != nil {
 		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
 	}
+	if err := openAIParts.check("OpenAI", template); err != nil {
+		return nil, err
+	}
 
 	opts := invokeOptions{
 		model:       "gpt-4o-mini",
@@ -67,47 +72,57 @@ func (o *OpenAIProvider) Invoke(ctx context.Context, template template.Template,
 		option(&opts)
 	}
 
+	opts.applyDeadline(ctx)
+
+	request := ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+		ServiceTier: opts.serviceTier,
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
 	// Validate required configuration
 	if o.options.apiKey == "" {
 		return nil, errorbank.NewValidationError("api_key", "OpenAI API key is required", "")
 	}
 
+	if err := o.checkHealth(); err != nil {
+		return nil, err
+	}
+
 	resp, err := failsafe.RetryWithResult(ctx, o.retrier, func() (*httpx.Response, error) {
-		return o.client.Post("/chat/completions", ChatCompletionsRequest{
-			Model: opts.model,
-			Messages: func() []Message {
-				templateMessages := template.GetMessage()
-				msgs := make([]Message, len(templateMessages))
-				for i, msg := range templateMessages {
-					msgs[i] = Message{
-						Role:    string(msg.GetRole()),
-						Content: msg.GetContent(),
-					}
-				}
-				return msgs
-			}(),
-			ResponseFormat: func() *ResponseFormat {
-				if opts.jsonSchema != nil {
-					return &ResponseFormat{
-						Type: "json_schema",
-						JsonSchema: JsonSchema{
-							Name:   "schema",
-							Strict: true,
-							Schema: opts.jsonSchema,
-						},
-					}
-				}
-				return nil
-			}(),
-		})
+		return o.post("/chat/completions", request)
 	})
 	if err != nil {
-		return nil, errorbank.NewMessageError("http_request", "failed to create request", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
-	var result ChatCompletionsResponse
-	if err := resp.Decode(&result); err != nil {
+	if err := o.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
 		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
 	}
 
@@ -116,9 +131,8 @@ func (o *OpenAIProvider) Invoke(ctx context.Context, template template.Template,
 	}
 
 	if opts.jsonSchema != nil {
-		err = json.Unmarshal([]byte(result.Choices[0].Message.Content), opts.structuredOutput)
-		if err != nil {
-			return nil, errorbank.NewMessageError("json_unmarshal", "failed to unmarshal structured output", err)
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
 		}
 	}
 
@@ -1,21 +1,28 @@
 package llm
 
 import (
-	"encoding/json"
-	"strings"
 	"time"
-
-	"github.com/invopop/jsonschema"
 )
 
 // llmOptions contains configuration options for LLM providers.
 // This struct is used internally to collect options during provider initialization.
 type llmOptions struct {
-	baseURL     string
-	apiKey      string
-	timeout     time.Duration
-	maxAttempts int
-	maxDelay    time.Duration
+	baseURL      string
+	apiKey       string
+	apiKeys      []string
+	keyStrategy  KeyStrategy
+	timeout      time.Duration
+	maxAttempts  int
+	maxDelay     time.Duration
+	quotaCooloff time.Duration
+	streamDecode bool
+	keepAlive    time.Duration
+	deployment   string
+	apiVersion   string
+	region       string
+	awsKeyID     string
+	awsSecret    string
+	awsToken     string
 }
 
 // LLMOption is a function type that modifies LLM options.
@@ -51,6 +58,42 @@ func WithAPIKey(apiKey string) LLMOption {
 	}
 }
 
+// WithAPIKeys configures the provider with multiple API keys and rotates
+// requests among them. Keys that receive a rate limit response are avoided
+// until their Retry-After window has passed, and keys that run out of quota
+// for the quota cool-off window; the rejected request moves on to the next
+// key. The provider is only marked unhealthy once every key is out of
+// quota. This is useful for teams that shard their quota across several keys.
+//
+// Example:
+//
+//	provider := NewOpenAI(
+//	  WithAPIKeys(os.Getenv("OPENAI_KEY_A"), os.Getenv("OPENAI_KEY_B")),
+//	  WithKeyStrategy(KeyLeastRecentlyThrottled),
+//	)
+func WithAPIKeys(apiKeys ...string) LLMOption {
+	return func(llm *llmOptions) {
+		llm.apiKeys = apiKeys
+		if len(apiKeys) > 0 {
+			llm.apiKey = apiKeys[0]
+		}
+	}
+}
+
+// WithKeyStrategy sets how the provider picks among the keys given to
+// WithAPIKeys. The default is KeyRoundRobin.
+//
+// Example:
+//
+//	provider := NewOpenAI(
+//	  WithKeyStrategy(KeyLeastRecentlyThrottled),
+//	)
+func WithKeyStrategy(strategy KeyStrategy) LLMOption {
+	return func(llm *llmOptions) {
+		llm.keyStrategy = strategy
+	}
+}
+
 // WithTimeout sets the timeout for HTTP requests to the LLM provider.
 // This prevents requests from hanging indefinitely and allows for
 // proper error handling and retry logic.
@@ -94,6 +137,113 @@ func WithMaxDelay(maxDelay time.Duration) LLMOption {
 	}
 }
 
+// WithQuotaCooloff sets how long the provider is marked unhealthy after
+// the API reports exhausted quota or credits. While cooling off, Invoke
+// fails fast instead of sending requests with a dead key.
+// A zero duration disables the cool-off.
+//
+// Example:
+//
+//	provider := NewOpenAI(
+//	  WithQuotaCooloff(10 * time.Minute),
+//	)
+func WithQuotaCooloff(cooloff time.Duration) LLMOption {
+	return func(llm *llmOptions) {
+		llm.quotaCooloff = cooloff
+	}
+}
+
+// WithStreamingDecode decodes responses directly from the HTTP body stream
+// instead of reading the whole body into memory first, which lowers peak
+// memory for large completions. Error statuses are still detected before
+// any decoding starts.
+//
+// Example:
+//
+//	provider := NewOpenAI(
+//	  WithStreamingDecode(),
+//	)
+func WithStreamingDecode() LLMOption {
+	return func(llm *llmOptions) {
+		llm.streamDecode = true
+	}
+}
+
+// WithKeepAlive sets how long Ollama keeps a model loaded after a request.
+// A negative duration keeps the model loaded indefinitely.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	provider := NewOllama(
+//	  WithKeepAlive(30 * time.Minute),
+//	)
+func WithKeepAlive(keepAlive time.Duration) LLMOption {
+	return func(llm *llmOptions) {
+		llm.keepAlive = keepAlive
+	}
+}
+
+// WithDeployment sets the Azure OpenAI deployment that serves requests.
+// This option is only used by the Azure OpenAI provider.
+//
+// Example:
+//
+//	provider := NewAzureOpenAI(
+//	  WithBaseURL("https://my-resource.openai.azure.com"),
+//	  WithDeployment("gpt-4o-prod"),
+//	)
+func WithDeployment(deployment string) LLMOption {
+	return func(llm *llmOptions) {
+		llm.deployment = deployment
+	}
+}
+
+// WithAPIVersion sets the api-version query parameter sent to Azure OpenAI.
+// This option is only used by the Azure OpenAI provider.
+//
+// Example:
+//
+//	provider := NewAzureOpenAI(
+//	  WithAPIVersion("2024-10-21"),
+//	)
+func WithAPIVersion(apiVersion string) LLMOption {
+	return func(llm *llmOptions) {
+		llm.apiVersion = apiVersion
+	}
+}
+
+// WithRegion sets the AWS region that serves requests.
+// This option is only used by the Bedrock provider.
+//
+// Example:
+//
+//	provider := NewBedrock(
+//	  WithRegion("eu-central-1"),
+//	)
+func WithRegion(region string) LLMOption {
+	return func(llm *llmOptions) {
+		llm.region = region
+	}
+}
+
+// WithAWSCredentials sets the AWS credentials used to sign requests.
+// The session token is only needed for temporary credentials and may be empty.
+// This option is only used by the Bedrock provider.
+//
+// Example:
+//
+//	provider := NewBedrock(
+//	  WithAWSCredentials(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")),
+//	)
+func WithAWSCredentials(accessKeyID, secretAccessKey, sessionToken string) LLMOption {
+	return func(llm *llmOptions) {
+		llm.awsKeyID = accessKeyID
+		llm.awsSecret = secretAccessKey
+		llm.awsToken = sessionToken
+	}
+}
+
 // invokeOptions contains configuration options for individual LLM requests.
 // These options can be customized per request to control the model's behavior.
 type invokeOptions struct {
@@ -102,6 +252,25 @@ type invokeOptions struct {
 	maxTokens        int
 	structuredOutput any
 	jsonSchema       map[string]any
+	outputValidator  OutputValidator
+	normalizePrompt  bool
+	dryRun           bool
+	mergeMessages    bool
+	mergeSeparator   string
+	serviceTier      string
+	imageDetail      string
+	deadlineBudget   time.Duration
+	deadlineModel    string
+	grammar          string
+	user             string
+	metadata         map[string]any
+	keepAlive        *time.Duration
+	contextWindow    int
+	gpuLayers        *int
+	repeatPenalty    float64
+	mirostat         int
+	mirostatTau      float64
+	mirostatEta      float64
 }
 
 // InvokeOption is a function type that modifies invoke options.
@@ -157,8 +326,30 @@ func WithMaxTokens(maxTokens int) InvokeOption {
 // The structured output is a pointer to a struct that will be used to unmarshal the response.
 // This is useful for returning structured data from the model.
 //
+// Fields are documented for the model with jsonschema struct tags, which
+// accept description, enum, minimum, maximum, and example keys. Keywords a
+// provider does not support are dropped when the request is built.
+//
+// Fields tagged omitempty are optional and pointer fields are nullable, so
+// the model can omit or null data that is not present in the input instead
+// of making up a value. Providers with a strict schema mode require every
+// field, so optional fields are sent to them as required but nullable.
+//
+// Nested struct types are described once in $defs, so recursive types such
+// as trees or reply chains are supported. Providers that cannot reference
+// definitions, such as Gemini, receive them expanded a few levels deep.
+//
+// Schemas are generated once per type and cached; use WarmSchemaCache to
+// generate them at startup.
+//
 // Example:
 //
+//	type StructuredOutput struct {
+//	  Sentiment  string  `json:"sentiment" jsonschema:"description=Overall tone of the text,enum=positive,enum=negative"`
+//	  Confidence float64 `json:"confidence" jsonschema:"minimum=0,maximum=1"`
+//	  Author     *string `json:"author" jsonschema:"description=Author if mentioned"`
+//	}
+//
 //	response, err := provider.Invoke(ctx, template,
 //	  WithStructuredOutput(&StructuredOutput{}),
 //	)
@@ -166,13 +357,277 @@ func WithStructuredOutput(structuredOutput any) InvokeOption {
 	return func(llm *invokeOptions) {
 		llm.structuredOutput = structuredOutput
 
-		llm.jsonSchema = func() map[string]any {
-			schema := jsonschema.Reflect(structuredOutput)
-			ref := strings.Split(schema.Ref, "#/$defs/")
-			schemaDefinition, _ := schema.Definitions[ref[1]].MarshalJSON()
-			var jsonSchema map[string]any
-			_ = json.Unmarshal(schemaDefinition, &jsonSchema)
-			return jsonSchema
-		}()
+		llm.jsonSchema = structuredSchema(structuredOutput)
+	}
+}
+
+// WithJSONSchema requests structured output matching a JSON schema that is
+// not derived from a Go type, such as a schema owned by another system.
+// The response is unmarshalled into output, which may be a pointer to a
+// struct or to a map[string]any.
+//
+// Example:
+//
+//	var schema map[string]any
+//	_ = json.Unmarshal(invoiceSchema, &schema)
+//
+//	var invoice map[string]any
+//	response, err := provider.Invoke(ctx, template,
+//	  WithJSONSchema(schema, &invoice),
+//	)
+func WithJSONSchema(schema map[string]any, output any) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.structuredOutput = output
+		llm.jsonSchema = schema
+	}
+}
+
+// WithOutputValidator validates structured output after it is decoded and
+// fails the request with an output_validation error when it does not pass.
+// The validator receives the output as decoded JSON, so a compiled JSON
+// Schema from pkg/schemacheck, or a CEL program wrapped in an
+// OutputValidatorFunc, can check it independently of Go types.
+//
+// Example:
+//
+//	schema := schemacheck.MustCompile(invoiceSchema)
+//	response, err := provider.Invoke(ctx, template,
+//	  WithStructuredOutput(&Invoice{}),
+//	  WithOutputValidator(schema),
+//	)
+func WithOutputValidator(validator OutputValidator) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.outputValidator = validator
+	}
+}
+
+// WithPromptNormalization enables whitespace normalization of every message
+// before it is sent. Trailing spaces, shared indentation, and repeated blank
+// lines are removed, which reduces prompt tokens for templates written as
+// indented Go string literals.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithPromptNormalization(),
+//	)
+func WithPromptNormalization() InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.normalizePrompt = true
+	}
+}
+
+// WithDryRun renders the request without calling the provider's API.
+// The returned message contains the JSON request body that would have been
+// sent, and its usage carries the estimated prompt tokens. No API key is
+// required, which makes dry runs suitable for budgeting and prompt-size checks in CI.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithDryRun(),
+//	)
+//	fmt.Println(response.GetUsage().PromptTokens)
+func WithDryRun() InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.dryRun = true
+	}
+}
+
+// WithMessageMerging merges consecutive messages with the same role before
+// sending, joining their contents with the separator. Some providers reject
+// repeated user messages, which template composition frequently produces.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithMessageMerging("\n\n"),
+//	)
+func WithMessageMerging(separator string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.mergeMessages = true
+		llm.mergeSeparator = separator
+	}
+}
+
+// WithServiceTier sets the processing tier of the request: "default",
+// "flex" for cheaper but slower processing, or "priority" for lower latency.
+// The tier is sent to providers that support it, such as OpenAI's
+// service_tier, and ignored by the others.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithServiceTier("flex"),
+//	)
+func WithServiceTier(tier string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.serviceTier = tier
+	}
+}
+
+// WithImageDetail sets the fidelity at which OpenAI-compatible providers read
+// attached images: "low" for a fixed small token cost per image, "high" for
+// full detail, or "auto", the default, to let the model decide. High detail
+// can cost many times more tokens than low, so use "low" when the gist of an
+// image is enough. Other providers ignore it.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithImageDetail("low"),
+//	)
+func WithImageDetail(detail string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.imageDetail = detail
+	}
+}
+
+// WithDeadlineDowngrade switches to a faster model when less than budget
+// remains before the context deadline, trading quality for the chance to
+// answer in time. A "flex" service tier is raised to "default" as well,
+// since flex processing can queue requests.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithModel("gpt-4o"),
+//	  WithDeadlineDowngrade(5*time.Second, "gpt-4o-mini"),
+//	)
+func WithDeadlineDowngrade(budget time.Duration, model string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.deadlineBudget = budget
+		llm.deadlineModel = model
+	}
+}
+
+// WithGrammar constrains the output to a GBNF grammar, e.g. to force one of
+// a fixed set of labels. It takes precedence over WithStructuredOutput.
+// This option is only used by the Fireworks provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithGrammar(`root ::= "positive" | "negative" | "neutral"`),
+//	)
+func WithGrammar(grammar string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.grammar = grammar
+	}
+}
+
+// WithUser identifies the end user on whose behalf the request is made,
+// which proxies such as LiteLLM use to attribute spend.
+// This option is only used by the LiteLLM provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithUser("user-1234"),
+//	)
+func WithUser(user string) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.user = user
+	}
+}
+
+// WithRequestMetadata attaches metadata to the request, such as the tags and
+// trace identifiers LiteLLM records for spend tracking. Repeated calls merge
+// their entries.
+// This option is only used by the LiteLLM provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithRequestMetadata(map[string]any{"tags": []string{"checkout"}, "trace_id": traceID}),
+//	)
+func WithRequestMetadata(metadata map[string]any) InvokeOption {
+	return func(llm *invokeOptions) {
+		if llm.metadata == nil {
+			llm.metadata = make(map[string]any, len(metadata))
+		}
+		for key, value := range metadata {
+			llm.metadata[key] = value
+		}
+	}
+}
+
+// WithRequestKeepAlive sets how long Ollama keeps the model loaded after this
+// request, overriding the provider's WithKeepAlive. A zero duration unloads
+// the model as soon as the request completes.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithRequestKeepAlive(time.Hour),
+//	)
+func WithRequestKeepAlive(keepAlive time.Duration) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.keepAlive = &keepAlive
+	}
+}
+
+// WithContextWindow sets the size of the context window in tokens (Ollama's
+// num_ctx). Ollama's default is small, so long prompts are truncated unless
+// it is raised.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithContextWindow(32768),
+//	)
+func WithContextWindow(tokens int) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.contextWindow = tokens
+	}
+}
+
+// WithGPULayers sets the number of model layers offloaded to the GPU
+// (Ollama's num_gpu). Zero runs the model on the CPU only.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithGPULayers(20),
+//	)
+func WithGPULayers(layers int) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.gpuLayers = &layers
+	}
+}
+
+// WithRepeatPenalty sets how strongly repetitions are penalized (Ollama's
+// repeat_penalty). Values above 1.0 discourage repeating tokens.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithRepeatPenalty(1.2),
+//	)
+func WithRepeatPenalty(penalty float64) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.repeatPenalty = penalty
+	}
+}
+
+// WithMirostat enables Mirostat sampling, which controls perplexity instead of
+// using a fixed temperature. Mode is 1 for Mirostat or 2 for Mirostat 2.0, tau
+// is the target entropy and eta the learning rate.
+// This option is only used by the Ollama provider.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template,
+//	  WithMirostat(2, 5.0, 0.1),
+//	)
+func WithMirostat(mode int, tau, eta float64) InvokeOption {
+	return func(llm *invokeOptions) {
+		llm.mirostat = mode
+		llm.mirostatTau = tau
+		llm.mirostatEta = eta
 	}
 }
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"fmt"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// partSupport describes the message parts a provider can send, so parts it
+// cannot serialize are rejected instead of being silently dropped.
+type partSupport struct {
+	// audioFormats lists the accepted audio encodings; nil means no audio input.
+	audioFormats map[string]bool
+
+	// imageFormats lists the accepted image encodings; nil means no image input.
+	imageFormats map[string]bool
+
+	// documents reports whether PDF documents may be attached.
+	documents bool
+
+	// fileIDs reports whether documents may reference uploaded files.
+	fileIDs bool
+}
+
+var (
+	// imageFormats are the image encodings accepted by most vision APIs.
+	imageFormats = map[string]bool{"jpeg": true, "png": true, "gif": true, "webp": true}
+
+	// openAIParts are the parts accepted by OpenAI chat completions.
+	openAIParts = partSupport{
+		audioFormats: map[string]bool{"wav": true, "mp3": true},
+		imageFormats: imageFormats,
+		documents:    true,
+		fileIDs:      true,
+	}
+
+	// geminiParts are the parts accepted by Gemini, which reads audio
+	// inline in more encodings than OpenAI but does not read GIF images.
+	geminiParts = partSupport{
+		audioFormats: map[string]bool{"wav": true, "mp3": true, "aac": true, "flac": true, "ogg": true, "aiff": true},
+		imageFormats: map[string]bool{"jpeg": true, "png": true, "webp": true},
+		documents:    true,
+	}
+
+	// anthropicParts are the parts accepted by Anthropic, which has no audio input.
+	anthropicParts = partSupport{imageFormats: imageFormats, documents: true, fileIDs: true}
+
+	// bedrockParts are the parts accepted by the Bedrock Converse API.
+	bedrockParts = partSupport{imageFormats: imageFormats, documents: true}
+
+	// mistralParts are the parts accepted by Mistral, whose audio and vision
+	// models read input_audio and image_url content but not files.
+	mistralParts = partSupport{
+		audioFormats: map[string]bool{"wav": true, "mp3": true},
+		imageFormats: imageFormats,
+	}
+
+	// openRouterParts are the parts OpenRouter forwards to the underlying
+	// model; uploaded files belong to a single provider and are not shared.
+	openRouterParts = partSupport{
+		audioFormats: map[string]bool{"wav": true, "mp3": true},
+		imageFormats: imageFormats,
+		documents:    true,
+	}
+
+	// visionParts are the parts accepted by OpenAI-compatible providers that
+	// serve vision models but no audio or file input, such as Groq, xAI,
+	// Together, and Fireworks.
+	visionParts = partSupport{imageFormats: imageFormats}
+
+	// textOnlyParts is used by providers that accept no parts at all, such as
+	// DeepSeek, Hugging Face, Ollama, and Cohere.
+	textOnlyParts = partSupport{}
+)
+
+// check returns a validation error for the first part of the template the
+// provider cannot send.
+func (s partSupport) check(provider string, template template.Template) error {
+	for _, msg := range template.GetMessage() {
+		for _, part := range msg.GetParts() {
+			switch {
+			case part.Type == message.PartAudio && s.audioFormats == nil:
+				return errorbank.NewValidationError("parts", provider+" does not support audio input", part.Format)
+			case part.Type == message.PartAudio && !s.audioFormats[part.Format]:
+				return errorbank.NewValidationError("format", fmt.Sprintf("%s does not support %s audio", provider, part.Format), part.Format)
+			case part.Type == message.PartImage && s.imageFormats == nil:
+				return errorbank.NewValidationError("parts", provider+" does not support image input", part.Format)
+			case part.Type == message.PartImage && !s.imageFormats[part.Format]:
+				return errorbank.NewValidationError("format", fmt.Sprintf("%s does not support %s images", provider, part.Format), part.Format)
+			case part.Type == message.PartDocument && !s.documents:
+				return errorbank.NewValidationError("parts", provider+" does not support document input", part.Name)
+			case part.FileID != "" && !s.fileIDs:
+				return errorbank.NewValidationError("file_id", provider+" does not support uploaded file references", part.FileID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package llm
+
+import "sync"
+
+// maxPooledMessages is the capacity above which message slices are not
+// returned to the pool, so one unusually long conversation does not stay
+// in memory.
+const maxPooledMessages = 256
+
+// messagePool holds the message slices rendered for chat completions
+// requests. A slice is only borrowed while its request is encoded and sent,
+// so it is reused by the next request instead of becoming garbage.
+var messagePool = sync.Pool{
+	New: func() any {
+		return new([]Message)
+	},
+}
+
+// getMessages returns a pooled message slice of length n.
+func getMessages(n int) []Message {
+	msgs := *messagePool.Get().(*[]Message)
+	if cap(msgs) < n {
+		return make([]Message, n)
+	}
+	return msgs[:n]
+}
+
+// putMessages returns a message slice to the pool. The slice must not be
+// used afterwards, so callers release it once the request has been sent.
+func putMessages(msgs []Message) {
+	if cap(msgs) > maxPooledMessages {
+		return
+	}
+	clear(msgs[:cap(msgs)])
+	msgs = msgs[:0]
+	messagePool.Put(&msgs)
+}
+
+// chatResponsePool holds the structs chat completions responses are decoded
+// into. Decoding reuses the capacity of a pooled Choices slice.
+var chatResponsePool = sync.Pool{
+	New: func() any {
+		return new(ChatCompletionsResponse)
+	},
+}
+
+// getChatResponse returns an empty pooled response.
+func getChatResponse() *ChatCompletionsResponse {
+	return chatResponsePool.Get().(*ChatCompletionsResponse)
+}
+
+// putChatResponse returns a response to the pool. Only strings and numbers
+// may be kept from it, since its slices and maps are reused.
+func putChatResponse(result *ChatCompletionsResponse) {
+	// Choices are zeroed so stale fields, such as logprobs, are not
+	// merged into the next decoded response.
+	choices := result.Choices[:cap(result.Choices)]
+	clear(choices)
+	*result = ChatCompletionsResponse{Choices: choices[:0]}
+	chatResponsePool.Put(result)
+}
@@ -1,9 +1,73 @@
 package llm
 
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/jsoncodec"
+	"github.com/bpradana/tars/template"
+)
+
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-	Refusal string `json:"refusal"`
+	Role             string        `json:"role"`
+	Content          string        `json:"content"`
+	Refusal          string        `json:"refusal"`
+	ReasoningContent string        `json:"reasoning_content,omitempty"`
+	Parts            []ContentPart `json:"-"`
+}
+
+// ContentPart is an element of a multimodal message content array.
+type ContentPart struct {
+	Type       string      `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+	File       *InputFile  `json:"file,omitempty"`
+	ImageURL   *ImageURL   `json:"image_url,omitempty"`
+}
+
+// InputAudio is a base64-encoded audio clip sent as model input.
+type InputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+// ImageURL is an image sent as model input as a base64 data URL, with the
+// detail at which the model reads it.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// InputFile is a document sent as model input, either inline as a base64
+// data URL or by the ID of an uploaded file.
+type InputFile struct {
+	FileData string `json:"file_data,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// MarshalJSON encodes the message content as a content array when the
+// message carries non-text parts, and as a plain string otherwise.
+func (m Message) MarshalJSON() ([]byte, error) {
+	type plain Message
+	if len(m.Parts) == 0 {
+		return jsoncodec.Marshal(plain(m))
+	}
+
+	content := make([]ContentPart, 0, len(m.Parts)+1)
+	if m.Content != "" {
+		content = append(content, ContentPart{Type: "text", Text: m.Content})
+	}
+	content = append(content, m.Parts...)
+
+	return jsoncodec.Marshal(struct {
+		Role    string        `json:"role"`
+		Content []ContentPart `json:"content"`
+	}{
+		Role:    m.Role,
+		Content: content,
+	})
 }
 
 type Choice struct {
@@ -26,14 +90,15 @@ type JsonSchema struct {
 }
 
 type ResponseFormat struct {
-	Type       string     `json:"type"`
-	JsonSchema JsonSchema `json:"json_schema"`
+	Type       string      `json:"type"`
+	JsonSchema *JsonSchema `json:"json_schema,omitempty"`
 }
 
 type ChatCompletionsRequest struct {
 	Model          string          `json:"model"`
 	Messages       []Message       `json:"messages"`
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	ServiceTier    string          `json:"service_tier,omitempty"`
 }
 
 type ChatCompletionsResponse struct {
@@ -46,3 +111,83 @@ type ChatCompletionsResponse struct {
 	SystemFingerprint string   `json:"system_fingerprint"`
 	Usage             Usage    `json:"usage"`
 }
+
+// newMessages converts the messages of a template into the chat message
+// format shared by the providers, applying any per-request preprocessing.
+// The slice is pooled; callers that do not keep it call putMessages once the
+// request has been sent.
+func newMessages(template template.Template, opts invokeOptions) []Message {
+	templateMessages := opts.messages(template)
+	msgs := getMessages(len(templateMessages))
+	for i, msg := range templateMessages {
+		content := msg.GetContent()
+		if opts.normalizePrompt {
+			content = message.Normalize(content)
+		}
+
+		msgs[i] = Message{
+			Role:    string(msg.GetRole()),
+			Content: content,
+			Parts:   newContentParts(msg.GetParts(), opts),
+		}
+	}
+	return msgs
+}
+
+// messages returns the messages of a template to send, merging consecutive
+// messages with the same role when requested.
+func (opts invokeOptions) messages(template template.Template) []message.Message {
+	if opts.mergeMessages {
+		return message.MergeConsecutive(template.GetMessage(), opts.mergeSeparator)
+	}
+	return template.GetMessage()
+}
+
+// newContentParts converts message parts into content array elements.
+func newContentParts(parts []message.Part, opts invokeOptions) []ContentPart {
+	if len(parts) == 0 {
+		return nil
+	}
+
+	contentParts := make([]ContentPart, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case message.PartAudio:
+			contentParts = append(contentParts, ContentPart{
+				Type: "input_audio",
+				InputAudio: &InputAudio{
+					Data:   base64.StdEncoding.EncodeToString(part.Data),
+					Format: part.Format,
+				},
+			})
+		case message.PartDocument:
+			file := &InputFile{FileID: part.FileID}
+			if part.FileID == "" {
+				file.FileData = "data:application/pdf;base64," + base64.StdEncoding.EncodeToString(part.Data)
+				file.Filename = documentFilename(part.Name)
+			}
+			contentParts = append(contentParts, ContentPart{Type: "file", File: file})
+		case message.PartImage:
+			contentParts = append(contentParts, ContentPart{
+				Type: "image_url",
+				ImageURL: &ImageURL{
+					URL:    "data:image/" + part.Format + ";base64," + base64.StdEncoding.EncodeToString(part.Data),
+					Detail: opts.imageDetail,
+				},
+			})
+		}
+	}
+	return contentParts
+}
+
+// documentFilename returns the file name of an inline PDF document, which
+// OpenAI requires to carry a .pdf extension.
+func documentFilename(name string) string {
+	if name == "" {
+		return "document.pdf"
+	}
+	if strings.HasSuffix(strings.ToLower(name), ".pdf") {
+		return name
+	}
+	return name + ".pdf"
+}
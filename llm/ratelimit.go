@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// RateLimits is the rate-limit state reported by a provider in the
+// x-ratelimit-* headers of its last response.
+type RateLimits struct {
+	// LimitRequests is the number of requests allowed in the window.
+	LimitRequests int
+
+	// RemainingRequests is the number of requests left in the window.
+	RemainingRequests int
+
+	// ResetRequests is when the request window resets.
+	ResetRequests time.Time
+
+	// LimitTokens is the number of tokens allowed in the window.
+	LimitTokens int
+
+	// RemainingTokens is the number of tokens left in the window.
+	RemainingTokens int
+
+	// ResetTokens is when the token window resets.
+	ResetTokens time.Time
+
+	// UpdatedAt is when the headers were received. It is zero until the
+	// provider has seen a response carrying rate-limit headers.
+	UpdatedAt time.Time
+}
+
+// RateLimitReporter is implemented by providers that expose the rate-limit
+// headers of their API.
+//
+// Example:
+//
+//	if reporter, ok := provider.(RateLimitReporter); ok {
+//	  limits := reporter.RateLimits()
+//	  fmt.Println(limits.RemainingTokens, "tokens left until", limits.ResetTokens)
+//	}
+type RateLimitReporter interface {
+	RateLimits() RateLimits
+}
+
+// IsRateLimited reports whether an error returned by Invoke was caused by
+// the provider rejecting the request with 429 Too Many Requests.
+//
+// Example:
+//
+//	if _, err := provider.Invoke(ctx, tmpl); llm.IsRateLimited(err) {
+//	  time.Sleep(time.Second)
+//	}
+func IsRateLimited(err error) bool {
+	var status *httpx.StatusError
+	return errors.As(err, &status) && status.StatusCode == http.StatusTooManyRequests
+}
+
+// rateLimitState holds the last rate limits seen by a provider.
+type rateLimitState struct {
+	mu     sync.RWMutex
+	limits RateLimits
+}
+
+// get returns the last rate limits seen.
+func (s *rateLimitState) get() RateLimits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+// record stores the rate limits of a response and returns them. Responses
+// without rate-limit headers leave the state untouched.
+func (s *rateLimitState) record(resp *httpx.Response) RateLimits {
+	if resp.GetHeader("x-ratelimit-limit-requests") == "" && resp.GetHeader("x-ratelimit-limit-tokens") == "" {
+		return s.get()
+	}
+
+	now := time.Now()
+	limits := RateLimits{
+		LimitRequests:     headerInt(resp, "x-ratelimit-limit-requests"),
+		RemainingRequests: headerInt(resp, "x-ratelimit-remaining-requests"),
+		ResetRequests:     now.Add(headerDuration(resp, "x-ratelimit-reset-requests")),
+		LimitTokens:       headerInt(resp, "x-ratelimit-limit-tokens"),
+		RemainingTokens:   headerInt(resp, "x-ratelimit-remaining-tokens"),
+		ResetTokens:       now.Add(headerDuration(resp, "x-ratelimit-reset-tokens")),
+		UpdatedAt:         now,
+	}
+
+	s.mu.Lock()
+	s.limits = limits
+	s.mu.Unlock()
+
+	return limits
+}
+
+// cooloff returns how long to back off after a rate-limited response,
+// preferring Retry-After and falling back to the reset of the exhausted window.
+func (l RateLimits) cooloff(resp *httpx.Response) time.Duration {
+	if resp.StatusCode() != http.StatusTooManyRequests {
+		return 0
+	}
+
+	var reset time.Time
+	if l.RemainingRequests == 0 && l.LimitRequests > 0 {
+		reset = l.ResetRequests
+	}
+	if l.RemainingTokens == 0 && l.LimitTokens > 0 && l.ResetTokens.After(reset) {
+		reset = l.ResetTokens
+	}
+
+	fallback := time.Until(reset)
+	if fallback <= 0 {
+		fallback = defaultThrottleWindow
+	}
+	return retryAfter(resp, fallback)
+}
+
+// headerInt parses an integer header, returning 0 when absent or malformed.
+func headerInt(resp *httpx.Response, key string) int {
+	value, _ := strconv.Atoi(resp.GetHeader(key))
+	return value
+}
+
+// headerDuration parses a duration header such as "2m59.56s" or "120ms",
+// returning 0 when absent or malformed.
+func headerDuration(resp *httpx.Response, key string) time.Duration {
+	value, _ := time.ParseDuration(resp.GetHeader(key))
+	return value
+}
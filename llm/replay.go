@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// TranscriptEntry is a single recorded invocation in a transcript file.
+type TranscriptEntry struct {
+	Time             time.Time `json:"time"`
+	Provider         string    `json:"provider"`
+	Messages         []Message `json:"messages"`
+	Fingerprint      string    `json:"fingerprint,omitempty"`
+	Response         string    `json:"response,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens,omitempty"`
+	CompletionTokens int       `json:"completion_tokens,omitempty"`
+	TotalTokens      int       `json:"total_tokens,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// RecorderProvider wraps a provider and appends every invocation to a
+// transcript file (one JSON entry per line) that ReplayProvider can serve.
+type RecorderProvider struct {
+	provider BaseProvider
+	mu       sync.Mutex
+	path     string
+}
+
+// NewRecorder creates a provider that records every invocation of the
+// given provider to the transcript file at path.
+//
+// Example:
+//
+//	provider := NewRecorder(NewOpenAI(WithAPIKey(key)), "incident-1234.jsonl")
+func NewRecorder(provider BaseProvider, path string) BaseProvider {
+	return &RecorderProvider{
+		provider: provider,
+		path:     path,
+	}
+}
+
+// GetName returns the name of the wrapped provider
+func (r *RecorderProvider) GetName() string {
+	return r.provider.GetName()
+}
+
+// Unwrap returns the wrapped provider.
+func (r *RecorderProvider) Unwrap() BaseProvider {
+	return r.provider
+}
+
+// Invoke calls the wrapped provider and appends the exchange to the transcript.
+func (r *RecorderProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	response, err := r.provider.Invoke(ctx, template, options...)
+
+	entry := TranscriptEntry{
+		Time:        time.Now(),
+		Provider:    r.provider.GetName(),
+		Messages:    newMessages(template, invokeOptions{}),
+		Fingerprint: template.Fingerprint(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		usage := response.GetUsage()
+		entry.Response = response.GetContent()
+		entry.PromptTokens = usage.PromptTokens
+		entry.CompletionTokens = usage.CompletionTokens
+		entry.TotalTokens = usage.TotalTokens
+	}
+
+	if recordErr := r.record(entry); recordErr != nil && err == nil {
+		return response, recordErr
+	}
+	return response, err
+}
+
+// record appends an entry to the transcript file.
+func (r *RecorderProvider) record(entry TranscriptEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errorbank.NewMessageError("transcript_write", "failed to open transcript file", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return errorbank.NewMessageError("transcript_write", "failed to write transcript entry", err)
+	}
+	return nil
+}
+
+// ReplayProvider serves responses from a recorded transcript instead of
+// calling an API, so production incidents can be reproduced locally.
+// Invocations are matched by the exact sequence of messages; identical
+// prompts recorded several times are served in recorded order, with the
+// last recording repeated once the others are used up.
+type ReplayProvider struct {
+	mu      sync.Mutex
+	entries map[string][]TranscriptEntry
+}
+
+// NewReplay loads the transcript at path and returns a provider that replays it.
+//
+// Example:
+//
+//	provider, err := NewReplay("incident-1234.jsonl")
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	response, err := provider.Invoke(ctx, template)
+func NewReplay(path string) (BaseProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errorbank.NewMessageError("transcript_read", "failed to open transcript file", err)
+	}
+	defer file.Close()
+
+	entries := make(map[string][]TranscriptEntry)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errorbank.NewMessageError("transcript_read", "failed to decode transcript entry", err)
+		}
+
+		key := transcriptKey(entry.Messages)
+		entries[key] = append(entries[key], entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorbank.NewMessageError("transcript_read", "failed to read transcript file", err)
+	}
+
+	return &ReplayProvider{entries: entries}, nil
+}
+
+// GetName returns the provider name
+func (r *ReplayProvider) GetName() string {
+	return "replay"
+}
+
+// Invoke returns the recorded response for the template's messages.
+// Recorded errors are returned as errors; unknown prompts fail with a "replay_miss" error.
+func (r *ReplayProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+
+	opts := invokeOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	msgs := newMessages(template, invokeOptions{})
+	key := transcriptKey(msgs)
+	putMessages(msgs)
+
+	r.mu.Lock()
+	queue := r.entries[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, errorbank.NewMessageError("replay_miss", "no recorded response for prompt", nil)
+	}
+	entry := queue[0]
+	if len(queue) > 1 {
+		r.entries[key] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	if entry.Error != "" {
+		return nil, errorbank.NewMessageError("replay", entry.Error, nil)
+	}
+
+	if opts.structuredOutput != nil {
+		if err := decodeStructuredOutput(entry.Response, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		entry.Response,
+		message.WithUsage(entry.PromptTokens, entry.CompletionTokens, entry.TotalTokens),
+	), nil
+}
+
+// transcriptKey identifies a prompt by its roles and contents.
+func transcriptKey(messages []Message) string {
+	key, _ := json.Marshal(messages)
+	return string(key)
+}
@@ -0,0 +1,397 @@
+package llm
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/jsoncodec"
+	"github.com/invopop/jsonschema"
+)
+
+// OutputValidator checks structured output. Validate receives the output
+// as decoded JSON: map[string]any, []any, string, float64, bool, or nil.
+type OutputValidator interface {
+	Validate(output any) error
+}
+
+// OutputValidatorFunc adapts a function to the OutputValidator interface,
+// for example to evaluate CEL expressions over the output.
+//
+// Example:
+//
+//	validator := llm.OutputValidatorFunc(func(output any) error {
+//	  out, _, err := program.Eval(map[string]any{"output": output})
+//	  if err != nil || out != types.True {
+//	    return fmt.Errorf("output rejected by policy")
+//	  }
+//	  return nil
+//	})
+type OutputValidatorFunc func(output any) error
+
+// Validate calls f(output).
+func (f OutputValidatorFunc) Validate(output any) error {
+	return f(output)
+}
+
+// decodeStructuredOutput unmarshals structured output content into the
+// requested value and applies the output validator, if any.
+func decodeStructuredOutput(content string, opts invokeOptions) error {
+	if err := jsoncodec.Unmarshal([]byte(content), opts.structuredOutput); err != nil {
+		return errorbank.NewMessageError("json_unmarshal", "failed to unmarshal structured output", err)
+	}
+
+	if opts.outputValidator == nil {
+		return nil
+	}
+
+	var output any
+	if err := jsoncodec.Unmarshal([]byte(content), &output); err != nil {
+		return errorbank.NewMessageError("json_unmarshal", "failed to unmarshal structured output", err)
+	}
+	if err := opts.outputValidator.Validate(output); err != nil {
+		return errorbank.NewMessageError("output_validation", "structured output failed validation", err)
+	}
+	return nil
+}
+
+// maxSchemaDepth is how many levels of a recursive type are expanded for
+// providers whose schemas cannot reference definitions.
+const maxSchemaDepth = 5
+
+// schemaCache holds the schemas generated by structuredSchema, keyed by the
+// reflect.Type of the structured output. Cached schemas are shared between
+// requests and must not be modified.
+var schemaCache sync.Map
+
+// WarmSchemaCache generates and caches the schemas of the given structured
+// output values, so the first requests using them at runtime do not pay
+// for reflection. Call it at startup with the types passed to
+// WithStructuredOutput; schemas are cached on first use otherwise.
+//
+// Example:
+//
+//	func init() {
+//	  llm.WarmSchemaCache(&Review{}, &Invoice{})
+//	}
+func WarmSchemaCache(structuredOutputs ...any) {
+	for _, structuredOutput := range structuredOutputs {
+		structuredSchema(structuredOutput)
+	}
+}
+
+// structuredSchema returns the JSON schema of a structured output value,
+// generating it with reflectSchema on the first use of its type.
+func structuredSchema(v any) map[string]any {
+	t := reflect.TypeOf(v)
+	if schema, ok := schemaCache.Load(t); ok {
+		return schema.(map[string]any)
+	}
+
+	schema, _ := schemaCache.LoadOrStore(t, reflectSchema(v))
+	return schema.(map[string]any)
+}
+
+// reflectSchema reflects the JSON schema of a structured output value.
+// Fields tagged omitempty are optional and pointer fields are nullable, so
+// the model can leave out or null data that is genuinely missing instead of
+// inventing a value. Nested struct types are kept in $defs and referenced,
+// which also describes recursive types such as trees.
+func reflectSchema(v any) map[string]any {
+	schema := jsonschema.Reflect(v)
+	name := strings.TrimPrefix(schema.Ref, "#/$defs/")
+
+	encoded, _ := json.Marshal(schema.Definitions)
+	var defs map[string]any
+	_ = json.Unmarshal(encoded, &defs)
+
+	root, _ := json.Marshal(schema.Definitions[name])
+	var jsonSchema map[string]any
+	_ = json.Unmarshal(root, &jsonSchema)
+
+	// The root is inlined; it only stays in $defs when a type refers back to it.
+	if !strings.Contains(string(encoded), `"#/$defs/`+name+`"`) {
+		delete(defs, name)
+	}
+
+	t := reflect.TypeOf(v)
+	visited := map[reflect.Type]bool{}
+	markNullable(jsonSchema, t, defs, visited)
+	if _, ok := defs[name]; ok {
+		markNullable(defs[name].(map[string]any), t, defs, visited)
+	}
+
+	if len(defs) > 0 {
+		jsonSchema["$defs"] = defs
+	}
+	return jsonSchema
+}
+
+// markNullable makes the properties of schema that are backed by pointer
+// fields of t nullable, descending into nested objects, arrays, and the
+// definitions they reference. Each referenced definition is visited once.
+func markNullable(schema map[string]any, t reflect.Type, defs map[string]any, visited map[reflect.Type]bool) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if schema == nil || t == nil {
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok || visited[t] {
+			return
+		}
+		visited[t] = true
+		schema = def
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := schema["items"].(map[string]any); ok {
+			markNullable(items, t.Elem(), defs, visited)
+		}
+	case reflect.Struct:
+		properties, _ := schema["properties"].(map[string]any)
+		markFieldsNullable(properties, t, defs, visited)
+	}
+}
+
+// markFieldsNullable applies markNullable to the properties backed by the
+// fields of struct t, including the fields promoted from embedded structs.
+func markFieldsNullable(properties map[string]any, t reflect.Type, defs map[string]any, visited map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				markFieldsNullable(properties, embedded, defs, visited)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		markNullable(property, field.Type, defs, visited)
+		if field.Type.Kind() == reflect.Pointer {
+			properties[name] = nullable(property)
+		}
+	}
+}
+
+// inlineSchemaRefs returns a copy of schema with every $ref replaced by the
+// definition it references, for providers that do not support $defs.
+// Recursive definitions are expanded maxSchemaDepth levels deep; deeper
+// properties are left out of the schema.
+func inlineSchemaRefs(schema map[string]any) map[string]any {
+	defs, _ := schema["$defs"].(map[string]any)
+	if len(defs) == 0 {
+		return schema
+	}
+	return inlineRefs(schema, defs, 0)
+}
+
+// inlineRefs implements inlineSchemaRefs. It returns nil when the schema
+// cannot be expanded within the depth limit.
+func inlineRefs(schema map[string]any, defs map[string]any, depth int) map[string]any {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok || depth >= maxSchemaDepth {
+			return nil
+		}
+		return inlineRefs(def, defs, depth+1)
+	}
+
+	result := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "$defs":
+			continue
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				break
+			}
+			converted := make(map[string]any, len(properties))
+			for name, property := range properties {
+				if property, ok := property.(map[string]any); ok {
+					if inlined := inlineRefs(property, defs, depth); inlined != nil {
+						converted[name] = inlined
+					}
+				}
+			}
+			value = converted
+		case "items":
+			if items, ok := value.(map[string]any); ok {
+				inlined := inlineRefs(items, defs, depth)
+				if inlined == nil {
+					return nil
+				}
+				value = inlined
+			}
+		case "anyOf", "oneOf", "allOf":
+			options, ok := value.([]any)
+			if !ok {
+				break
+			}
+			converted := make([]any, 0, len(options))
+			expanded := false
+			for _, option := range options {
+				if option, ok := option.(map[string]any); ok {
+					if inlined := inlineRefs(option, defs, depth); inlined != nil {
+						converted = append(converted, inlined)
+						expanded = expanded || inlined["type"] != "null"
+					}
+				}
+			}
+			if !expanded {
+				return nil
+			}
+			value = converted
+		}
+		result[key] = value
+	}
+
+	// Left out properties can no longer be required.
+	if properties, ok := result["properties"].(map[string]any); ok {
+		if names, ok := result["required"].([]any); ok {
+			required := make([]any, 0, len(names))
+			for _, name := range names {
+				if name, ok := name.(string); ok && properties[name] != nil {
+					required = append(required, name)
+				}
+			}
+			result["required"] = required
+		}
+	}
+	return result
+}
+
+// nullable returns schema extended to also accept null.
+func nullable(schema map[string]any) map[string]any {
+	switch typ := schema["type"].(type) {
+	case string:
+		if typ != "null" {
+			schema["type"] = []any{typ, "null"}
+		}
+		return schema
+	case []any:
+		for _, t := range typ {
+			if t == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(append([]any{}, typ...), "null")
+		return schema
+	}
+
+	if options, ok := schema["anyOf"].([]any); ok {
+		for _, option := range options {
+			if option, ok := option.(map[string]any); ok && option["type"] == "null" {
+				return schema
+			}
+		}
+		schema["anyOf"] = append(options, map[string]any{"type": "null"})
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// strictSchema returns a copy of schema that satisfies strict structured
+// output modes, which require every property to be listed as required.
+// Optional properties are made required but nullable instead, so the model
+// can still answer null for them.
+func strictSchema(schema map[string]any) map[string]any {
+	if schema == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "properties", "$defs":
+			if schemas, ok := value.(map[string]any); ok {
+				value = strictSchemas(schemas)
+			}
+		case "items", "additionalProperties", "not":
+			if nested, ok := value.(map[string]any); ok {
+				value = strictSchema(nested)
+			}
+		case "anyOf", "oneOf", "allOf":
+			if options, ok := value.([]any); ok {
+				converted := make([]any, len(options))
+				for i, option := range options {
+					if nested, ok := option.(map[string]any); ok {
+						option = strictSchema(nested)
+					}
+					converted[i] = option
+				}
+				value = converted
+			}
+		}
+		result[key] = value
+	}
+
+	properties, ok := result["properties"].(map[string]any)
+	if !ok {
+		return result
+	}
+
+	required := make(map[string]bool)
+	if names, ok := result["required"].([]any); ok {
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name, property := range properties {
+		names = append(names, name)
+		if property, ok := property.(map[string]any); ok && !required[name] {
+			properties[name] = nullable(property)
+		}
+	}
+	sort.Strings(names)
+
+	all := make([]any, len(names))
+	for i, name := range names {
+		all[i] = name
+	}
+	result["required"] = all
+	return result
+}
+
+// strictSchemas applies strictSchema to every schema of a keyed set, such
+// as the properties of an object.
+func strictSchemas(schemas map[string]any) map[string]any {
+	result := make(map[string]any, len(schemas))
+	for name, schema := range schemas {
+		if nested, ok := schema.(map[string]any); ok {
+			schema = strictSchema(nested)
+		}
+		result[name] = schema
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package llm
+
+import (
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+var (
+	// anthropicShape is the conversation shape accepted by the Messages API.
+	anthropicShape = template.ProfileAnthropic
+
+	// geminiShape is the conversation shape accepted by generateContent.
+	geminiShape = template.ProfileGemini
+
+	// bedrockShape is the conversation shape accepted by the Converse API.
+	bedrockShape = template.ProfileBedrock
+)
+
+// validateShape checks the conversation that will be sent, after any
+// message merging, against the shape profile of a provider, so role orders
+// the provider would reject fail before a request is made.
+func validateShape(profile template.Profile, tmpl template.Template, opts invokeOptions) error {
+	if opts.mergeMessages {
+		tmpl = template.From(opts.messages(tmpl)...)
+	}
+	if err := tmpl.Validate(profile); err != nil {
+		return errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	return nil
+}
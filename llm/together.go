@@ -0,0 +1,186 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+	"github.com/bpradana/tars/template"
+)
+
+// TogetherProvider implements the BaseProvider interface for Together AI
+type TogetherProvider struct {
+	baseProvider
+}
+
+// NewTogether creates a new Together AI provider for open-weight models
+func NewTogether(options ...LLMOption) BaseProvider {
+	opts := llmOptions{
+		baseURL:      "https://api.together.xyz/v1",
+		timeout:      10 * time.Second,
+		maxAttempts:  1,
+		maxDelay:     0 * time.Second,
+		quotaCooloff: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &TogetherProvider{
+		baseProvider: baseProvider{
+			options: opts,
+			client: httpx.NewClient().
+				WithBaseURL(opts.baseURL).
+				WithDefaultHeaders(httpx.NewHeader().Bearer(opts.apiKey)).
+				WithTimeout(opts.timeout).
+				WithStreamingDecode(opts.streamDecode),
+			retrier: failsafe.NewRetrier(
+				failsafe.WithMaxAttempts(opts.maxAttempts),
+				failsafe.WithDelayStrategy(strategies.NewFixedDelay(opts.maxDelay)),
+			),
+			keys: newKeyPool(opts.apiKeys, opts.keyStrategy),
+		},
+	}
+}
+
+// togetherDefaultModel is the model used when none is given.
+const togetherDefaultModel = "meta-llama/Llama-3.3-70B-Instruct-Turbo"
+
+// togetherDefaults holds the recommended sampling settings of popular
+// Together models. Reasoning models need a lower temperature and a much
+// larger token budget than chat models.
+var togetherDefaults = map[string]invokeOptions{
+	"meta-llama/Llama-3.3-70B-Instruct-Turbo":       {temperature: 0.7, maxTokens: 1000},
+	"meta-llama/Meta-Llama-3.1-8B-Instruct-Turbo":   {temperature: 0.7, maxTokens: 1000},
+	"meta-llama/Meta-Llama-3.1-405B-Instruct-Turbo": {temperature: 0.7, maxTokens: 1000},
+	"Qwen/Qwen2.5-72B-Instruct-Turbo":               {temperature: 0.7, maxTokens: 1000},
+	"Qwen/QwQ-32B":                                  {temperature: 0.6, maxTokens: 8000},
+	"deepseek-ai/DeepSeek-V3":                       {temperature: 0.7, maxTokens: 1000},
+	"deepseek-ai/DeepSeek-R1":                       {temperature: 0.6, maxTokens: 8000},
+	"mistralai/Mixtral-8x7B-Instruct-v0.1":          {temperature: 0.7, maxTokens: 1000},
+}
+
+// togetherModelDefaults returns the default invoke options for a model.
+func togetherModelDefaults(model string) invokeOptions {
+	opts, ok := togetherDefaults[model]
+	if !ok {
+		opts = invokeOptions{temperature: 0.7, maxTokens: 1000}
+	}
+	opts.model = model
+	return opts
+}
+
+// togetherRequest is a chat completions request with sampling settings.
+type togetherRequest struct {
+	ChatCompletionsRequest
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens,omitempty"`
+}
+
+// GetName returns the provider name
+func (t *TogetherProvider) GetName() string {
+	return "together"
+}
+
+// Invoke implements the BaseProvider interface for Together AI
+func (t *TogetherProvider) Invoke(ctx context.Context, template template.Template, options ...InvokeOption) (message.Message, error) {
+	// Validate the template before processing
+	if err := template.Validate(); err != nil {
+		return nil, errorbank.NewMessageError("template_validation", "invalid template provided", err)
+	}
+	if err := visionParts.check("Together", template); err != nil {
+		return nil, err
+	}
+
+	opts := invokeOptions{model: togetherDefaultModel}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	// Apply the defaults of the selected model, then the caller's options again.
+	opts = togetherModelDefaults(opts.model)
+	for _, option := range options {
+		option(&opts)
+	}
+
+	opts.applyDeadline(ctx)
+
+	request := togetherRequest{
+		Temperature: opts.temperature,
+		MaxTokens:   opts.maxTokens,
+	}
+	request.ChatCompletionsRequest = ChatCompletionsRequest{
+		Model:    opts.model,
+		Messages: newMessages(template, opts),
+		ResponseFormat: func() *ResponseFormat {
+			if opts.jsonSchema != nil {
+				return &ResponseFormat{
+					Type: "json_schema",
+					JsonSchema: &JsonSchema{
+						Name:   "schema",
+						Strict: true,
+						Schema: strictSchema(opts.jsonSchema),
+					},
+				}
+			}
+			return nil
+		}(),
+	}
+
+	defer putMessages(request.Messages)
+
+	if opts.dryRun {
+		return dryRun(request, template, opts)
+	}
+
+	// Validate required configuration
+	if t.options.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", "Together API key is required", "")
+	}
+
+	if err := t.checkHealth(); err != nil {
+		return nil, err
+	}
+
+	resp, err := failsafe.RetryWithResult(ctx, t.retrier, func() (*httpx.Response, error) {
+		return t.post("/chat/completions", request)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := t.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := getChatResponse()
+	defer putChatResponse(result)
+	if err := resp.Decode(result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, errorbank.NewMessageError("no_choices", "no choices in response", nil)
+	}
+
+	if opts.jsonSchema != nil {
+		if err := decodeStructuredOutput(result.Choices[0].Message.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.FromAssistant(
+		result.Choices[0].Message.Content,
+		message.WithUsage(
+			result.Usage.PromptTokens,
+			result.Usage.CompletionTokens,
+			result.Usage.TotalTokens,
+		),
+	), nil
+}
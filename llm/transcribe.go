@@ -0,0 +1,268 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Transcriber is implemented by providers that convert speech to text.
+type Transcriber interface {
+	// Transcribe uploads the audio read from r, named filename, and returns
+	// its transcription. The filename extension tells the provider the
+	// audio format, such as "meeting.mp3" or "note.wav".
+	Transcribe(ctx context.Context, r io.Reader, filename string, options ...TranscribeOption) (Transcription, error)
+}
+
+// Transcription is the text recognized in an audio file. Language, Duration
+// and Segments are only reported when segment or word timestamps are
+// requested with WithTimestampGranularity.
+type Transcription struct {
+	Text     string                 `json:"text"`
+	Language string                 `json:"language,omitempty"`
+	Duration time.Duration          `json:"duration,omitempty"`
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+}
+
+// TranscriptionSegment is a timed span of a transcription.
+type TranscriptionSegment struct {
+	ID    int           `json:"id"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Text  string        `json:"text"`
+}
+
+// TranscriptionWord is a single timed word of a transcription.
+type TranscriptionWord struct {
+	Word  string        `json:"word"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// transcribeOptions contains the options of a transcription request.
+type transcribeOptions struct {
+	model       string
+	language    string
+	prompt      string
+	temperature *float64
+	granularity string
+}
+
+// TranscribeOption is a function type that modifies a transcription request.
+type TranscribeOption func(*transcribeOptions)
+
+// WithTranscriptionModel sets the speech-to-text model.
+//
+// Example:
+//
+//	text, err := provider.Transcribe(ctx, file, "call.mp3", WithTranscriptionModel("whisper-large-v3"))
+func WithTranscriptionModel(model string) TranscribeOption {
+	return func(o *transcribeOptions) {
+		o.model = model
+	}
+}
+
+// WithLanguage sets the ISO-639-1 language of the audio, such as "en" or
+// "id". Giving the language improves accuracy and latency; otherwise it is
+// detected by the provider.
+//
+// Example:
+//
+//	text, err := provider.Transcribe(ctx, file, "call.mp3", WithLanguage("id"))
+func WithLanguage(language string) TranscribeOption {
+	return func(o *transcribeOptions) {
+		o.language = language
+	}
+}
+
+// WithTranscriptionPrompt guides the transcription with text in the same
+// language as the audio, such as the spelling of names and jargon or the
+// transcript of the previous part of a recording.
+//
+// Example:
+//
+//	text, err := provider.Transcribe(ctx, file, "standup.m4a", WithTranscriptionPrompt("Attendees: Bayu, Ningsih. Topics: TARS, RAG."))
+func WithTranscriptionPrompt(prompt string) TranscribeOption {
+	return func(o *transcribeOptions) {
+		o.prompt = prompt
+	}
+}
+
+// WithTranscriptionTemperature sets the sampling temperature, between 0 and 1.
+//
+// Example:
+//
+//	text, err := provider.Transcribe(ctx, file, "call.mp3", WithTranscriptionTemperature(0))
+func WithTranscriptionTemperature(temperature float64) TranscribeOption {
+	return func(o *transcribeOptions) {
+		o.temperature = &temperature
+	}
+}
+
+// WithTimestampGranularity requests timestamps of the given granularity,
+// "segment" or "word", along with the detected language and duration of
+// the audio.
+//
+// Example:
+//
+//	text, err := provider.Transcribe(ctx, file, "call.mp3", WithTimestampGranularity("segment"))
+//	for _, segment := range text.Segments {
+//	  fmt.Printf("[%s] %s\n", segment.Start, segment.Text)
+//	}
+func WithTimestampGranularity(granularity string) TranscribeOption {
+	return func(o *transcribeOptions) {
+		o.granularity = granularity
+	}
+}
+
+// transcriptionResponse is the json and verbose_json response of the
+// OpenAI-compatible transcriptions endpoint. Times are in seconds.
+type transcriptionResponse struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+	Segments []struct {
+		ID    int     `json:"id"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+	Words []struct {
+		Word  string  `json:"word"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
+}
+
+// Transcribe implements the Transcriber interface for OpenAI using Whisper.
+//
+// Example:
+//
+//	file, _ := os.Open("meeting.mp3")
+//	defer file.Close()
+//	text, err := provider.(llm.Transcriber).Transcribe(ctx, file, "meeting.mp3", llm.WithLanguage("en"))
+func (o *OpenAIProvider) Transcribe(ctx context.Context, r io.Reader, filename string, options ...TranscribeOption) (Transcription, error) {
+	if o.options.apiKey == "" {
+		return Transcription{}, errorbank.NewValidationError("api_key", "OpenAI API key is required", "")
+	}
+	return o.transcribe(ctx, r, filename, "whisper-1", options)
+}
+
+// Transcribe implements the Transcriber interface for Groq using Whisper.
+//
+// Example:
+//
+//	file, _ := os.Open("meeting.mp3")
+//	defer file.Close()
+//	text, err := provider.(llm.Transcriber).Transcribe(ctx, file, "meeting.mp3", llm.WithLanguage("en"))
+func (g *GroqProvider) Transcribe(ctx context.Context, r io.Reader, filename string, options ...TranscribeOption) (Transcription, error) {
+	if g.options.apiKey == "" {
+		return Transcription{}, errorbank.NewValidationError("api_key", "Groq API key is required", "")
+	}
+	return g.transcribe(ctx, r, filename, "whisper-large-v3-turbo", options)
+}
+
+// transcribe uploads audio to the OpenAI-compatible /audio/transcriptions
+// endpoint. The audio is streamed and can only be read once, so the request
+// is not retried. The upload is bound to ctx and canceled with it.
+func (b *baseProvider) transcribe(ctx context.Context, r io.Reader, filename, model string, options []TranscribeOption) (Transcription, error) {
+	opts := transcribeOptions{model: model}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if filename == "" {
+		return Transcription{}, errorbank.NewValidationError("filename", "audio filename is required", "")
+	}
+
+	if err := b.checkHealth(); err != nil {
+		return Transcription{}, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Transcription{}, errorbank.NewMessageError("http_request", "request canceled before sending", err)
+	}
+
+	fields := map[string]string{
+		"model":           opts.model,
+		"response_format": "json",
+	}
+	if opts.language != "" {
+		fields["language"] = opts.language
+	}
+	if opts.prompt != "" {
+		fields["prompt"] = opts.prompt
+	}
+	if opts.temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*opts.temperature, 'f', -1, 64)
+	}
+	if opts.granularity != "" {
+		fields["response_format"] = "verbose_json"
+		fields["timestamp_granularities[]"] = opts.granularity
+	}
+
+	req, err := b.client.POST("/audio/transcriptions")
+	if err != nil {
+		return Transcription{}, errorbank.NewMessageError("http_request", "failed to create request", err)
+	}
+	req.Request = req.Request.WithContext(ctx)
+
+	var key string
+	if b.keys != nil {
+		key = b.keys.next()
+		req.WithHeader("Authorization", "Bearer "+key)
+	}
+
+	resp, err := req.WithMultipart(fields, httpx.FormFile{Field: "file", Filename: filename, Reader: r}).Do()
+	if err != nil {
+		return Transcription{}, errorbank.NewMessageError("http_request", "failed to send request", err)
+	}
+	defer resp.Body.Close()
+
+	if key != "" {
+		if err := b.recordKey(key, resp); err != nil {
+			return Transcription{}, err
+		}
+	}
+
+	if err := b.checkResponse(resp); err != nil {
+		return Transcription{}, err
+	}
+
+	var result transcriptionResponse
+	if err := resp.Decode(&result); err != nil {
+		return Transcription{}, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	transcription := Transcription{
+		Text:     result.Text,
+		Language: result.Language,
+		Duration: seconds(result.Duration),
+	}
+	for _, segment := range result.Segments {
+		transcription.Segments = append(transcription.Segments, TranscriptionSegment{
+			ID:    segment.ID,
+			Start: seconds(segment.Start),
+			End:   seconds(segment.End),
+			Text:  segment.Text,
+		})
+	}
+	for _, word := range result.Words {
+		transcription.Words = append(transcription.Words, TranscriptionWord{
+			Word:  word.Word,
+			Start: seconds(word.Start),
+			End:   seconds(word.End),
+		})
+	}
+	return transcription, nil
+}
+
+// seconds converts fractional seconds into a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
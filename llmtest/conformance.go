@@ -0,0 +1,253 @@
+package llmtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/template"
+)
+
+// conformanceOptions contains configuration options for a conformance run.
+type conformanceOptions struct {
+	invokeOptions    []llm.InvokeOption
+	structuredOutput bool
+	timeout          time.Duration
+}
+
+// ConformanceOption is a function type that modifies conformance run options.
+type ConformanceOption func(*conformanceOptions)
+
+// WithInvokeOptions adds invoke options to every call made by the suite,
+// such as the model to test against.
+//
+// Example:
+//
+//	llmtest.RunProviderConformance(t, provider, llmtest.WithInvokeOptions(llm.WithModel("my-model")))
+func WithInvokeOptions(options ...llm.InvokeOption) ConformanceOption {
+	return func(o *conformanceOptions) {
+		o.invokeOptions = append(o.invokeOptions, options...)
+	}
+}
+
+// WithoutStructuredOutput skips the structured output checks, for providers
+// that do not support WithStructuredOutput.
+//
+// Example:
+//
+//	llmtest.RunProviderConformance(t, provider, llmtest.WithoutStructuredOutput())
+func WithoutStructuredOutput() ConformanceOption {
+	return func(o *conformanceOptions) {
+		o.structuredOutput = false
+	}
+}
+
+// WithCallTimeout sets how long a single call may take before the suite
+// fails it. The default is 30 seconds.
+//
+// Example:
+//
+//	llmtest.RunProviderConformance(t, provider, llmtest.WithCallTimeout(2*time.Minute))
+func WithCallTimeout(timeout time.Duration) ConformanceOption {
+	return func(o *conformanceOptions) {
+		o.timeout = timeout
+	}
+}
+
+// conformanceAnswer is the structured output requested by the suite.
+type conformanceAnswer struct {
+	Answer     string  `json:"answer" jsonschema:"description=The answer to the question"`
+	Confidence float64 `json:"confidence" jsonschema:"description=Confidence in the answer between 0 and 1"`
+}
+
+// RunProviderConformance runs the BaseProvider contract checks against a
+// provider as subtests of t. The provider must answer simple prompts, so
+// point it at a mock server or a real endpoint. It checks that:
+//
+//   - GetName returns a non-empty name
+//   - invalid templates are rejected with a validation error from errorbank
+//   - successful calls return a valid assistant message and leave the
+//     template unmodified
+//   - the common invoke options are accepted
+//   - structured output decodes into the requested value
+//   - canceled contexts stop the call with an error wrapping the context error
+//
+// Example:
+//
+//	func TestMyProviderConformance(t *testing.T) {
+//	  server := httptest.NewServer(fakeCompletions())
+//	  defer server.Close()
+//	  llmtest.RunProviderConformance(t, NewMyProvider(server.URL))
+//	}
+func RunProviderConformance(t *testing.T, provider llm.BaseProvider, options ...ConformanceOption) {
+	t.Helper()
+
+	opts := conformanceOptions{
+		structuredOutput: true,
+		timeout:          30 * time.Second,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	c := conformance{provider: provider, options: opts}
+	t.Run("Name", c.testName)
+	t.Run("Validation", c.testValidation)
+	t.Run("Invoke", c.testInvoke)
+	t.Run("Options", c.testOptions)
+	if opts.structuredOutput {
+		t.Run("StructuredOutput", c.testStructuredOutput)
+	}
+	t.Run("ContextCancellation", c.testContextCancellation)
+}
+
+// conformance holds the state shared by the conformance subtests.
+type conformance struct {
+	provider llm.BaseProvider
+	options  conformanceOptions
+}
+
+// invoke calls the provider with the suite's invoke options, turning a
+// panic into an error so one broken check does not abort the whole suite.
+func (c conformance) invoke(ctx context.Context, tmpl template.Template, options ...llm.InvokeOption) (response message.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Invoke panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.options.timeout)
+	defer cancel()
+	return c.provider.Invoke(ctx, tmpl, append(append([]llm.InvokeOption{}, c.options.invokeOptions...), options...)...)
+}
+
+// checkResponse fails t unless a successful call returned a valid
+// assistant message and left the template unmodified.
+func checkResponse(t *testing.T, tmpl template.Template, fingerprint string, response message.Message) {
+	t.Helper()
+
+	if tmpl.Fingerprint() != fingerprint {
+		t.Error("Invoke modified the template")
+	}
+	if response == nil {
+		t.Fatal("Invoke returned a nil message without an error")
+	}
+	if response.GetRole() != message.RoleAssistant {
+		t.Errorf("Invoke returned a message with role %q, want %q", response.GetRole(), message.RoleAssistant)
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("Invoke returned an invalid message: %v", err)
+	}
+}
+
+func (c conformance) testName(t *testing.T) {
+	if c.provider.GetName() == "" {
+		t.Error("GetName returned an empty name")
+	}
+}
+
+func (c conformance) testValidation(t *testing.T) {
+	invalid := map[string]template.Template{
+		"empty template": template.From(),
+		"empty message":  template.From(message.FromUser("")),
+		"invalid part":   template.From(message.FromUser("Listen.", message.WithAudio(nil, "wav"))),
+	}
+
+	for name, tmpl := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.invoke(context.Background(), tmpl)
+			if err == nil {
+				t.Fatal("Invoke accepted an invalid template")
+			}
+
+			var validationErr *errorbank.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Invoke returned %T (%v), want an error wrapping *errorbank.ValidationError", err, err)
+			}
+		})
+	}
+}
+
+func (c conformance) testInvoke(t *testing.T) {
+	tmpl := template.From(
+		message.FromSystem("You answer in one short sentence."),
+		message.FromUser("What is the capital of France?"),
+	)
+	fingerprint := tmpl.Fingerprint()
+
+	response, err := c.invoke(context.Background(), tmpl)
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	checkResponse(t, tmpl, fingerprint, response)
+}
+
+func (c conformance) testOptions(t *testing.T) {
+	tmpl := template.From(
+		message.FromUser("Say hello."),
+		message.FromUser("Keep it short."),
+	)
+	fingerprint := tmpl.Fingerprint()
+
+	response, err := c.invoke(context.Background(), tmpl,
+		llm.WithTemperature(0),
+		llm.WithMaxTokens(64),
+		llm.WithPromptNormalization(),
+		llm.WithMessageMerging("\n"),
+	)
+	if err != nil {
+		t.Fatalf("Invoke failed with common options: %v", err)
+	}
+	checkResponse(t, tmpl, fingerprint, response)
+}
+
+func (c conformance) testStructuredOutput(t *testing.T) {
+	tmpl := template.From(message.FromUser("What is the capital of France? Answer with your confidence."))
+	fingerprint := tmpl.Fingerprint()
+
+	var answer conformanceAnswer
+	response, err := c.invoke(context.Background(), tmpl, llm.WithStructuredOutput(&answer))
+	if err != nil {
+		t.Fatalf("Invoke failed with structured output: %v", err)
+	}
+	checkResponse(t, tmpl, fingerprint, response)
+
+	var want conformanceAnswer
+	if err := json.Unmarshal([]byte(response.GetContent()), &want); err != nil {
+		t.Fatalf("structured output content is not valid JSON: %v", err)
+	}
+	if answer != want {
+		t.Errorf("structured output was decoded as %+v, want %+v", answer, want)
+	}
+}
+
+func (c conformance) testContextCancellation(t *testing.T) {
+	tmpl := template.From(message.FromUser("Say hello."))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.invoke(ctx, tmpl)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Invoke succeeded with a canceled context")
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Invoke returned %v, want an error wrapping context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Invoke did not return within 5s of a canceled context")
+	}
+}
@@ -0,0 +1,311 @@
+// Package llmtest helps users validate their own provider implementations
+// against the BaseProvider contract.
+package llmtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/template"
+)
+
+// StressReport summarizes a stress run.
+type StressReport struct {
+	// Requests is the number of Invoke calls made.
+	Requests int
+
+	// Errors is the number of calls that returned an error.
+	Errors int
+
+	// Violations describes the calls whose results broke the BaseProvider
+	// contract, such as a nil message without an error or a template that
+	// was modified by Invoke.
+	Violations []string
+
+	// Elapsed is the wall time of the run.
+	Elapsed time.Duration
+
+	// Throughput is the number of calls completed per second.
+	Throughput float64
+
+	// P50, P95, P99, and Max are the latencies of the calls.
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// stressOptions contains configuration options for a stress run.
+type stressOptions struct {
+	concurrency int
+	requests    int
+	duration    time.Duration
+	templates   []template.Template
+	optionSets  [][]llm.InvokeOption
+	seed        int64
+}
+
+// StressOption is a function type that modifies stress run options.
+type StressOption func(*stressOptions)
+
+// WithConcurrency sets how many goroutines invoke the provider at once.
+// The default is 8.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithConcurrency(32))
+func WithConcurrency(concurrency int) StressOption {
+	return func(o *stressOptions) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithRequests sets the total number of Invoke calls. The default is 100.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithRequests(1000))
+func WithRequests(requests int) StressOption {
+	return func(o *stressOptions) {
+		o.requests = requests
+	}
+}
+
+// WithDuration stops the run after the given duration, even if fewer calls
+// than WithRequests were made. Zero, the default, runs every request.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithRequests(math.MaxInt), WithDuration(time.Minute))
+func WithDuration(duration time.Duration) StressOption {
+	return func(o *stressOptions) {
+		o.duration = duration
+	}
+}
+
+// WithTemplates sets the templates sent to the provider; each call picks
+// one at random. The default is a single short user message.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithTemplates(greeting, summary))
+func WithTemplates(templates ...template.Template) StressOption {
+	return func(o *stressOptions) {
+		o.templates = templates
+	}
+}
+
+// WithOptionSets adds sets of invoke options; each call applies one set at
+// random on top of a randomized temperature and token limit. Use it to
+// exercise the options a provider handles specially.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithOptionSets(
+//	  []llm.InvokeOption{llm.WithModel("gpt-4o-mini")},
+//	  []llm.InvokeOption{llm.WithModel("gpt-4o"), llm.WithPromptNormalization()},
+//	))
+func WithOptionSets(optionSets ...[]llm.InvokeOption) StressOption {
+	return func(o *stressOptions) {
+		o.optionSets = append(o.optionSets, optionSets...)
+	}
+}
+
+// WithSeed seeds the random choice of templates and options so a failing
+// run can be reproduced. By default the seed is derived from the time.
+//
+// Example:
+//
+//	runner := NewStressRunner(provider, WithSeed(42))
+func WithSeed(seed int64) StressOption {
+	return func(o *stressOptions) {
+		o.seed = seed
+	}
+}
+
+// StressRunner drives a provider concurrently with randomized templates and
+// options, checking every result against the BaseProvider contract and
+// measuring throughput. Run it from a test with the race detector enabled
+// (go test -race) to catch data races in a provider implementation.
+type StressRunner struct {
+	provider llm.BaseProvider
+	options  stressOptions
+}
+
+// NewStressRunner creates a stress runner for the given provider, which may
+// be a mock or a real provider.
+//
+// Example:
+//
+//	func TestProviderStress(t *testing.T) {
+//	  report, err := llmtest.NewStressRunner(NewMyProvider(),
+//	    llmtest.WithConcurrency(16),
+//	    llmtest.WithRequests(500),
+//	  ).Run(context.Background())
+//	  if err != nil {
+//	    t.Fatal(err)
+//	  }
+//	  for _, violation := range report.Violations {
+//	    t.Error(violation)
+//	  }
+//	  t.Logf("%.0f req/s, p99 %s", report.Throughput, report.P99)
+//	}
+func NewStressRunner(provider llm.BaseProvider, options ...StressOption) *StressRunner {
+	opts := stressOptions{
+		concurrency: 8,
+		requests:    100,
+		seed:        time.Now().UnixNano(),
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if len(opts.templates) == 0 {
+		opts.templates = []template.Template{
+			template.From(message.FromUser("Reply with a short greeting.")),
+		}
+	}
+
+	return &StressRunner{provider: provider, options: opts}
+}
+
+// stressCall is a single planned Invoke call.
+type stressCall struct {
+	template template.Template
+	options  []llm.InvokeOption
+}
+
+// stressResult is the outcome of a single call.
+type stressResult struct {
+	latency   time.Duration
+	err       error
+	violation string
+}
+
+// Run invokes the provider until every request was made, the duration
+// elapsed, or ctx is done. Errors returned by the provider are counted,
+// not returned; Run only fails when the runner is misconfigured.
+func (r *StressRunner) Run(ctx context.Context) (StressReport, error) {
+	if r.provider == nil {
+		return StressReport{}, fmt.Errorf("stress runner has no provider")
+	}
+	if r.options.concurrency < 1 || r.options.requests < 1 {
+		return StressReport{}, fmt.Errorf("concurrency and requests must be positive")
+	}
+
+	if r.options.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.duration)
+		defer cancel()
+	}
+
+	// Calls are planned up front from a single source, since rand.Rand is
+	// not safe for concurrent use.
+	random := rand.New(rand.NewSource(r.options.seed))
+	start := time.Now()
+	calls := make(chan stressCall)
+	go func() {
+		defer close(calls)
+		for i := 0; i < r.options.requests; i++ {
+			select {
+			case calls <- r.plan(random):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan stressResult)
+	var wg sync.WaitGroup
+	for i := 0; i < r.options.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for call := range calls {
+				results <- r.invoke(ctx, call)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var report StressReport
+	var latencies []time.Duration
+	for result := range results {
+		report.Requests++
+		latencies = append(latencies, result.latency)
+		if result.err != nil {
+			report.Errors++
+		}
+		if result.violation != "" {
+			report.Violations = append(report.Violations, result.violation)
+		}
+	}
+	report.Elapsed = time.Since(start)
+
+	if report.Elapsed > 0 {
+		report.Throughput = float64(report.Requests) / report.Elapsed.Seconds()
+	}
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		report.P50 = percentile(latencies, 0.50)
+		report.P95 = percentile(latencies, 0.95)
+		report.P99 = percentile(latencies, 0.99)
+		report.Max = latencies[len(latencies)-1]
+	}
+	return report, nil
+}
+
+// plan picks the template and options of the next call.
+func (r *StressRunner) plan(random *rand.Rand) stressCall {
+	options := []llm.InvokeOption{
+		llm.WithTemperature(float64(random.Intn(11)) / 10),
+		llm.WithMaxTokens(16 + random.Intn(1009)),
+	}
+	if len(r.options.optionSets) > 0 {
+		options = append(options, r.options.optionSets[random.Intn(len(r.options.optionSets))]...)
+	}
+
+	return stressCall{
+		template: r.options.templates[random.Intn(len(r.options.templates))],
+		options:  options,
+	}
+}
+
+// invoke makes a call and checks its result against the BaseProvider contract.
+func (r *StressRunner) invoke(ctx context.Context, call stressCall) stressResult {
+	fingerprint := call.template.Fingerprint()
+
+	start := time.Now()
+	response, err := r.provider.Invoke(ctx, call.template, call.options...)
+	result := stressResult{latency: time.Since(start), err: err}
+
+	switch {
+	case call.template.Fingerprint() != fingerprint:
+		result.violation = "Invoke modified the template"
+	case err != nil:
+	case response == nil:
+		result.violation = "Invoke returned a nil message without an error"
+	case response.GetRole() != message.RoleAssistant:
+		result.violation = fmt.Sprintf("Invoke returned a message with role %q, want %q", response.GetRole(), message.RoleAssistant)
+	default:
+		if err := response.Validate(); err != nil {
+			result.violation = fmt.Sprintf("Invoke returned an invalid message: %v", err)
+		}
+	}
+	return result
+}
+
+// percentile returns the latency at quantile q of sorted latencies.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	index := int(q*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(index, len(sorted)-1))]
+}
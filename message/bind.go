@@ -0,0 +1,168 @@
+package message
+
+import (
+	"bytes"
+	"strings"
+	"text/template/parse"
+)
+
+// Bind substitutes the variables present in vars and leaves every other
+// placeholder in place, so the message can be bound in stages and finished
+// with Invoke. Actions are rendered only when all the variables they read
+// are bound; anything else, including partial references and actions that
+// read the whole data value with {{.}}, is kept verbatim.
+//
+// Example:
+//
+//	msg := FromUser("{{.Greeting}}, {{.Name}}!").Bind(map[string]any{"Greeting": "Hello"})
+//	// msg.GetContent() == "Hello, {{.Name}}!"
+func (m message) Bind(vars map[string]any) Message {
+	if len(vars) == 0 {
+		return &m
+	}
+
+	trees, err := parse.Parse("message", m.Content, "", "", builtinFuncs)
+	if err != nil {
+		return &m
+	}
+	tree := trees["message"]
+	if tree == nil || tree.Root == nil {
+		return &m
+	}
+
+	var content strings.Builder
+	for _, node := range tree.Root.Nodes {
+		if text, ok := node.(*parse.TextNode); ok {
+			content.Write(text.Text)
+			continue
+		}
+
+		// A top-level declaration is visible to later nodes, so it cannot
+		// be rendered on its own.
+		action, isAction := node.(*parse.ActionNode)
+		if isAction && len(action.Pipe.Decl) > 0 || !isBound(node, vars, true, map[string]bool{}) {
+			content.WriteString(node.String())
+			continue
+		}
+
+		rendered, err := renderNode(node, vars)
+		if err != nil {
+			content.WriteString(node.String())
+			continue
+		}
+		content.WriteString(rendered)
+	}
+
+	return &message{
+		Role:      m.Role,
+		Content:   content.String(),
+		Usage:     m.Usage,
+		Parts:     m.Parts,
+		Reasoning: m.Reasoning,
+		Metadata:  m.Metadata,
+	}
+}
+
+// builtinFuncs declares the text/template builtins so that parsing accepts
+// them; the values are never called.
+var builtinFuncs = map[string]any{
+	"and": true, "call": true, "html": true, "index": true, "slice": true,
+	"js": true, "len": true, "not": true, "or": true, "print": true,
+	"printf": true, "println": true, "urlquery": true,
+	"eq": true, "ge": true, "gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// renderNode executes a single top-level node against vars.
+func renderNode(node parse.Node, vars map[string]any) (string, error) {
+	tmpl, err := newRenderTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err = tmpl.Parse(node.String())
+	if err != nil {
+		return "", err
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, vars); err != nil {
+		return "", err
+	}
+	return content.String(), nil
+}
+
+// isBound reports whether every data reference in node resolves to a key of
+// vars. root is false inside range and with bodies, where dot no longer
+// refers to the template data. declared tracks variables declared within
+// the node itself.
+func isBound(node parse.Node, vars map[string]any, root bool, declared map[string]bool) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case *parse.TextNode, *parse.CommentNode, *parse.BoolNode, *parse.NumberNode,
+		*parse.StringNode, *parse.NilNode, *parse.IdentifierNode,
+		*parse.BreakNode, *parse.ContinueNode:
+		return true
+	case *parse.DotNode:
+		return !root
+	case *parse.FieldNode:
+		if !root {
+			return true
+		}
+		_, ok := vars[n.Ident[0]]
+		return ok
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			if len(n.Ident) == 1 {
+				return false
+			}
+			_, ok := vars[n.Ident[1]]
+			return ok
+		}
+		return declared[n.Ident[0]]
+	case *parse.ChainNode:
+		return isBound(n.Node, vars, root, declared)
+	case *parse.PipeNode:
+		if n == nil {
+			return true
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				if !isBound(arg, vars, root, declared) {
+					return false
+				}
+			}
+		}
+		for _, decl := range n.Decl {
+			declared[decl.Ident[0]] = true
+		}
+		return true
+	case *parse.ActionNode:
+		return isBound(n.Pipe, vars, root, declared)
+	case *parse.ListNode:
+		if n == nil {
+			return true
+		}
+		for _, child := range n.Nodes {
+			if !isBound(child, vars, root, declared) {
+				return false
+			}
+		}
+		return true
+	case *parse.IfNode:
+		return isBound(n.Pipe, vars, root, declared) &&
+			isBound(n.List, vars, root, declared) &&
+			isBound(n.ElseList, vars, root, declared)
+	case *parse.RangeNode:
+		return isBound(n.Pipe, vars, root, declared) &&
+			isBound(n.List, vars, false, declared) &&
+			isBound(n.ElseList, vars, root, declared)
+	case *parse.WithNode:
+		return isBound(n.Pipe, vars, root, declared) &&
+			isBound(n.List, vars, false, declared) &&
+			isBound(n.ElseList, vars, root, declared)
+	default:
+		// Partials and blocks read data we cannot inspect here.
+		return false
+	}
+}
@@ -2,21 +2,30 @@ package message
 
 import (
 	"bytes"
-	"encoding/json"
-	"text/template"
+	"slices"
 
 	"github.com/bpradana/tars/pkg/errorbank"
 )
 
 // Message represents a conversation message with role, content, and usage information.
 // It provides methods for template variable substitution and JSON serialization.
+//
+// Messages are immutable: Invoke and Bind return new messages, and GetParts
+// and GetMetadata return copies, so a message can be shared between
+// goroutines without synchronization. Part data is shared between copies
+// and must not be modified; use Clone for a fully independent copy.
 type Message interface {
 	GetRole() RoleType
 	GetContent() string
 	GetUsage() usage
+	GetParts() []Part
+	GetReasoning() string
+	GetMetadata() map[string]any
 	Invoke(v any) Message
-	ToJSON() string
+	Bind(vars map[string]any) Message
+	ToJSON(options ...JSONOption) string
 	Validate() error
+	Clone() Message
 }
 
 // usage tracks token usage information for LLM requests
@@ -28,9 +37,12 @@ type usage struct {
 
 // message implements the Message interface
 type message struct {
-	Role    RoleType
-	Content string
-	Usage   usage
+	Role      RoleType
+	Content   string
+	Usage     usage
+	Parts     []Part         `json:",omitempty"`
+	Reasoning string         `json:",omitempty"`
+	Metadata  map[string]any `json:",omitempty"`
 }
 
 func (m message) GetRole() RoleType {
@@ -45,38 +57,83 @@ func (m message) GetUsage() usage {
 	return m.Usage
 }
 
+// GetParts returns a copy of the non-text parts of the message. The data of
+// the parts is shared with the message and must not be modified.
+func (m message) GetParts() []Part {
+	return slices.Clone(m.Parts)
+}
+
+// GetReasoning returns the reasoning the model produced before its answer,
+// for providers that expose it. It is empty otherwise.
+func (m message) GetReasoning() string {
+	return m.Reasoning
+}
+
+// GetMetadata returns a copy of the application metadata attached with
+// WithMetadata, or nil if there is none.
+func (m message) GetMetadata() map[string]any {
+	return cloneMetadata(m.Metadata)
+}
+
 // Invoke performs template variable substitution on the message content.
 // It creates a new message with substituted content without modifying the original.
 func (m message) Invoke(v any) Message {
 	if v == nil {
-		return m
+		return &m
+	}
+
+	tmpl, err := newRenderTemplate()
+	if err != nil {
+		return &m
 	}
 
-	tmpl, err := template.New("message").Parse(m.Content)
+	tmpl, err = tmpl.Parse(m.Content)
 	if err != nil {
-		return m
+		return &m
 	}
 
 	var content bytes.Buffer
 	if err := tmpl.Execute(&content, v); err != nil {
-		return m
+		return &m
 	}
 
-	return message{
-		Role:    m.Role,
-		Content: content.String(),
-		Usage:   m.Usage,
+	return &message{
+		Role:      m.Role,
+		Content:   content.String(),
+		Usage:     m.Usage,
+		Parts:     m.Parts,
+		Reasoning: m.Reasoning,
+		Metadata:  m.Metadata,
+	}
+}
+
+// Clone returns a deep copy of the message, including the data of its parts
+// and its metadata.
+func (m message) Clone() Message {
+	parts := slices.Clone(m.Parts)
+	for i := range parts {
+		parts[i].Data = bytes.Clone(parts[i].Data)
+	}
+
+	return &message{
+		Role:      m.Role,
+		Content:   m.Content,
+		Usage:     m.Usage,
+		Parts:     parts,
+		Reasoning: m.Reasoning,
+		Metadata:  cloneMetadata(m.Metadata),
 	}
 }
 
 // ToJSON serializes the message to JSON string format.
+// Options can add an estimated token count and pretty-print the output.
 // Returns an empty string if serialization fails.
-func (m message) ToJSON() string {
-	json, err := json.Marshal(m)
-	if err != nil {
-		return ""
+func (m message) ToJSON(options ...JSONOption) string {
+	opts := newJSONOptions(options)
+	if opts.annotate {
+		return marshal(annotate(m), opts)
 	}
-	return string(json)
+	return marshal(m, opts)
 }
 
 // Validate checks if the message is valid and returns an error if not.
@@ -86,10 +143,16 @@ func (m message) Validate() error {
 		return errorbank.NewValidationError("role", "cannot be empty", m.Role)
 	}
 
-	if m.Content == "" {
+	if m.Content == "" && len(m.Parts) == 0 {
 		return errorbank.NewValidationError("content", "cannot be empty", m.Content)
 	}
 
+	for _, part := range m.Parts {
+		if err := part.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Validate role type
 	switch m.Role {
 	case RoleSystem, RoleUser, RoleAssistant:
@@ -0,0 +1,111 @@
+package message
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// resizedJPEGQuality is the quality used to re-encode downscaled JPEG images.
+const resizedJPEGQuality = 85
+
+// ResizeImage downscales a JPEG or PNG image so neither side exceeds
+// maxDimension pixels, keeping its aspect ratio and format. Providers resize
+// large images themselves but still bill and time out on the bytes sent, and
+// Anthropic and Bedrock reject images over a few megabytes, so resizing before
+// WithImage keeps vision calls cheap and within limits. Images that already
+// fit are returned unchanged.
+//
+// Useful limits are 2048 for OpenAI high detail, 512 for OpenAI low detail,
+// and 1568 for Claude.
+//
+// Parameters:
+//   - data: The raw JPEG or PNG bytes
+//   - maxDimension: The largest width or height allowed, in pixels
+//
+// Returns:
+//   - The resized image bytes
+//   - The image format, "jpeg" or "png", to pass to WithImage
+//   - An error if the image cannot be decoded or encoded
+//
+// Example:
+//
+//	screenshot, format, err := ResizeImage(raw, 2048)
+//	if err != nil {
+//	  return err
+//	}
+//	msg := FromUser("What is wrong with this dialog?", WithImage(screenshot, format))
+func ResizeImage(data []byte, maxDimension int) ([]byte, string, error) {
+	if maxDimension < 1 {
+		return nil, "", errorbank.NewValidationError("max_dimension", "must be positive", maxDimension)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, "", errorbank.NewValidationError("data", "not a JPEG or PNG image", format)
+	}
+	if config.Width <= maxDimension && config.Height <= maxDimension {
+		return bytes.Clone(data), format, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", errorbank.NewMessageError("image_decode", "failed to decode image", err)
+	}
+
+	width, height := maxDimension, maxDimension
+	if config.Width > config.Height {
+		height = max(1, config.Height*maxDimension/config.Width)
+	} else {
+		width = max(1, config.Width*maxDimension/config.Height)
+	}
+	dst := downscale(src, width, height)
+
+	var buf bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: resizedJPEGQuality})
+	}
+	if err != nil {
+		return nil, "", errorbank.NewMessageError("image_encode", "failed to encode resized image", err)
+	}
+	return buf.Bytes(), format, nil
+}
+
+// downscale resizes src to width by height by averaging the source pixels
+// covered by each destination pixel, which avoids the aliasing of
+// nearest-neighbor sampling when shrinking.
+func downscale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(b / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,166 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// jsonOptions contains configuration options for JSON serialization.
+type jsonOptions struct {
+	annotate bool
+	indent   bool
+}
+
+// JSONOption is a function type that modifies how messages are serialized by ToJSON.
+type JSONOption func(*jsonOptions)
+
+// WithTokenAnnotations adds an estimated token count to every serialized
+// message, and a total to serialized transcripts, which helps spot the parts
+// of a prompt that dominate its cost.
+//
+// Example:
+//
+//	fmt.Println(template.ToJSON(message.WithTokenAnnotations()))
+func WithTokenAnnotations() JSONOption {
+	return func(o *jsonOptions) {
+		o.annotate = true
+	}
+}
+
+// WithIndent produces pretty multi-line JSON instead of a single line.
+//
+// Example:
+//
+//	fmt.Println(msg.ToJSON(message.WithIndent()))
+func WithIndent() JSONOption {
+	return func(o *jsonOptions) {
+		o.indent = true
+	}
+}
+
+// annotatedMessage is the serialized form of a message with token annotations.
+type annotatedMessage struct {
+	Role            RoleType
+	Content         string
+	EstimatedTokens int
+	Usage           usage
+	Parts           []Part         `json:",omitempty"`
+	Reasoning       string         `json:",omitempty"`
+	Metadata        map[string]any `json:",omitempty"`
+}
+
+// annotatedTranscript is the serialized form of a message list with token annotations.
+type annotatedTranscript struct {
+	Messages        []annotatedMessage
+	EstimatedTokens int
+}
+
+// TranscriptJSON serializes a list of messages, such as the messages of a
+// template, to JSON string format using the given options. Without options
+// the result is a plain JSON array of messages.
+// Returns an empty string if serialization fails.
+//
+// Example:
+//
+//	fmt.Println(TranscriptJSON(messages, WithTokenAnnotations(), WithIndent()))
+func TranscriptJSON(messages []Message, options ...JSONOption) string {
+	opts := newJSONOptions(options)
+	if !opts.annotate {
+		return marshal(messages, opts)
+	}
+
+	transcript := annotatedTranscript{Messages: make([]annotatedMessage, len(messages))}
+	for i, m := range messages {
+		transcript.Messages[i] = annotate(m)
+		transcript.EstimatedTokens += transcript.Messages[i].EstimatedTokens
+	}
+	return marshal(transcript, opts)
+}
+
+// FromJSON reconstructs a message serialized by ToJSON, including its role,
+// content, usage, parts, reasoning, and metadata. Token annotations are
+// ignored. Metadata numbers are decoded as float64, as with encoding/json.
+// The message is not validated; call Validate before sending it.
+//
+// Example:
+//
+//	msg, err := message.FromJSON([]byte(stored))
+//	if err != nil {
+//	  return err
+//	}
+func FromJSON(data []byte) (Message, error) {
+	var m message
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errorbank.NewMessageError("json_unmarshal", "failed to decode message", err)
+	}
+	return &m, nil
+}
+
+// TranscriptFromJSON reconstructs a list of messages serialized by
+// TranscriptJSON, with or without token annotations.
+//
+// Example:
+//
+//	messages, err := message.TranscriptFromJSON([]byte(stored))
+func TranscriptFromJSON(data []byte) ([]Message, error) {
+	var decoded []message
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var transcript struct {
+			Messages []message
+		}
+		if err := json.Unmarshal(data, &transcript); err != nil {
+			return nil, errorbank.NewMessageError("json_unmarshal", "failed to decode transcript", err)
+		}
+		decoded = transcript.Messages
+	} else if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, errorbank.NewMessageError("json_unmarshal", "failed to decode transcript", err)
+	}
+
+	messages := make([]Message, len(decoded))
+	for i := range decoded {
+		messages[i] = &decoded[i]
+	}
+	return messages, nil
+}
+
+// newJSONOptions applies options to the default JSON options.
+func newJSONOptions(options []JSONOption) jsonOptions {
+	var opts jsonOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// annotate converts a message to its annotated serialized form.
+func annotate(m Message) annotatedMessage {
+	return annotatedMessage{
+		Role:            m.GetRole(),
+		Content:         m.GetContent(),
+		EstimatedTokens: EstimateTokens(m.GetContent()),
+		Usage:           m.GetUsage(),
+		Parts:           m.GetParts(),
+		Reasoning:       m.GetReasoning(),
+		Metadata:        m.GetMetadata(),
+	}
+}
+
+// marshal encodes v honoring the indent option.
+// Returns an empty string if serialization fails.
+func marshal(v any, opts jsonOptions) string {
+	var (
+		data []byte
+		err  error
+	)
+	if opts.indent {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,55 @@
+package message
+
+import "strings"
+
+// MergeConsecutive merges runs of consecutive messages with the same role
+// into a single message. Contents are joined with the separator, skipping
+// empty contents, and parts are kept in order. Some providers reject
+// repeated user messages, which template composition easily produces.
+//
+// Example:
+//
+//	merged := MergeConsecutive(messages, "\n\n")
+func MergeConsecutive(messages []Message, separator string) []Message {
+	merged := make([]Message, 0, len(messages))
+	for i := 0; i < len(messages); {
+		j := i + 1
+		for j < len(messages) && messages[j].GetRole() == messages[i].GetRole() {
+			j++
+		}
+
+		if j == i+1 {
+			merged = append(merged, messages[i])
+			i = j
+			continue
+		}
+
+		var (
+			contents []string
+			parts    []Part
+			total    usage
+			metadata map[string]any
+		)
+		for _, m := range messages[i:j] {
+			if m.GetContent() != "" {
+				contents = append(contents, m.GetContent())
+			}
+			parts = append(parts, m.GetParts()...)
+			u := m.GetUsage()
+			total.PromptTokens += u.PromptTokens
+			total.CompletionTokens += u.CompletionTokens
+			total.TotalTokens += u.TotalTokens
+			metadata = mergeMetadata(metadata, m.GetMetadata())
+		}
+
+		merged = append(merged, &message{
+			Role:     messages[i].GetRole(),
+			Content:  strings.Join(contents, separator),
+			Usage:    total,
+			Parts:    parts,
+			Metadata: metadata,
+		})
+		i = j
+	}
+	return merged
+}
@@ -5,21 +5,22 @@ package message
 // They are typically sent at the beginning of a conversation to define
 // the assistant's personality, capabilities, or constraints.
 //
+// Options can attach metadata.
+//
 // Example:
 //
 //	msg := FromSystem("You are a helpful assistant that specializes in math.")
-func FromSystem(content string) Message {
-	if content == "" {
-		// Return a message that will fail validation rather than panic
-		return &message{
-			Role:    RoleSystem,
-			Content: "",
-		}
+func FromSystem(content string, options ...MessageOption) Message {
+	opts := messageOptions{}
+	for _, option := range options {
+		option(&opts)
 	}
 
+	// An empty content returns a message that will fail validation rather than panic
 	return &message{
-		Role:    RoleSystem,
-		Content: content,
+		Role:     RoleSystem,
+		Content:  content,
+		Metadata: opts.metadata,
 	}
 }
 
@@ -27,21 +28,22 @@ func FromSystem(content string) Message {
 // User messages represent input from the user that the assistant should respond to.
 // These messages can contain questions, requests, or any other user input.
 //
+// Options can attach non-text content such as audio, and metadata.
+//
 // Example:
 //
 //	msg := FromUser("What is the capital of France?")
-func FromUser(content string) Message {
-	if content == "" {
-		// Return a message that will fail validation rather than panic
-		return &message{
-			Role:    RoleUser,
-			Content: "",
-		}
+func FromUser(content string, options ...MessageOption) Message {
+	opts := messageOptions{}
+	for _, option := range options {
+		option(&opts)
 	}
 
 	return &message{
-		Role:    RoleUser,
-		Content: content,
+		Role:     RoleUser,
+		Content:  content,
+		Parts:    opts.parts,
+		Metadata: opts.metadata,
 	}
 }
 
@@ -61,8 +63,29 @@ func FromAssistant(content string, options ...MessageOption) Message {
 	}
 
 	return &message{
-		Role:    RoleAssistant,
-		Content: content,
-		Usage:   opts.usage,
+		Role:      RoleAssistant,
+		Content:   content,
+		Usage:     opts.usage,
+		Parts:     opts.parts,
+		Reasoning: opts.reasoning,
+		Metadata:  opts.metadata,
+	}
+}
+
+// CopyWithContent returns a copy of the message with its content replaced,
+// keeping the role, usage, parts, reasoning, and metadata. It is used by
+// alternate template engines that render content outside of Invoke.
+//
+// Example:
+//
+//	rendered := CopyWithContent(msg, strings.ReplaceAll(msg.GetContent(), "{{name}}", "Alice"))
+func CopyWithContent(m Message, content string) Message {
+	return &message{
+		Role:      m.GetRole(),
+		Content:   content,
+		Usage:     m.GetUsage(),
+		Parts:     m.GetParts(),
+		Reasoning: m.GetReasoning(),
+		Metadata:  m.GetMetadata(),
 	}
 }
@@ -0,0 +1,70 @@
+package message
+
+import "maps"
+
+// WithMetadata attaches application data to a message, such as a timestamp,
+// the speaker's name, or a trace ID. Metadata is never sent to providers;
+// it is kept by ToJSON so chat history can be persisted with it. Calling
+// WithMetadata more than once merges the maps, later keys winning. The map
+// is copied, so the caller may reuse it.
+//
+// Example:
+//
+//	msg := FromUser("Where is my order?",
+//	  WithMetadata(map[string]any{
+//	    "speaker":  "alice",
+//	    "trace_id": span.SpanContext().TraceID().String(),
+//	    "sent_at":  time.Now().UTC().Format(time.RFC3339),
+//	  }))
+func WithMetadata(metadata map[string]any) MessageOption {
+	return func(m *messageOptions) {
+		m.metadata = mergeMetadata(m.metadata, metadata)
+	}
+}
+
+// mergeMetadata returns a copy of dst with the entries of src added.
+// It returns nil when both are empty.
+func mergeMetadata(dst, src map[string]any) map[string]any {
+	if len(dst) == 0 && len(src) == 0 {
+		return nil
+	}
+
+	merged := cloneMetadata(dst)
+	if merged == nil {
+		merged = make(map[string]any, len(src))
+	}
+	for key, value := range cloneMetadata(src) {
+		merged[key] = value
+	}
+	return merged
+}
+
+// cloneMetadata returns a deep copy of metadata, copying the nested maps
+// and slices produced by JSON decoding. Other values are copied as is.
+func cloneMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+
+	clone := maps.Clone(metadata)
+	for key, value := range clone {
+		clone[key] = cloneValue(value)
+	}
+	return clone
+}
+
+// cloneValue deep copies a metadata value.
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return cloneMetadata(v)
+	case []any:
+		clone := make([]any, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return value
+	}
+}
@@ -0,0 +1,73 @@
+package message
+
+import (
+	"strings"
+)
+
+// Normalize cleans up whitespace in prompt content without changing its meaning.
+// It strips trailing spaces, removes the indentation shared by all lines
+// (as produced by indented Go raw string literals), collapses runs of blank
+// lines into a single blank line, and trims leading and trailing blank lines.
+//
+// Example:
+//
+//	content := Normalize(`
+//	    You are a helpful assistant.
+//
+//
+//	    Answer briefly.   `)
+//	// content == "You are a helpful assistant.\n\nAnswer briefly."
+func Normalize(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	indent := commonIndent(lines)
+
+	normalized := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		if line == "" {
+			if !blank && len(normalized) > 0 {
+				normalized = append(normalized, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		normalized = append(normalized, strings.TrimPrefix(line, indent))
+	}
+
+	// Drop the trailing blank line kept by the collapsing loop
+	if len(normalized) > 0 && normalized[len(normalized)-1] == "" {
+		normalized = normalized[:len(normalized)-1]
+	}
+
+	return strings.Join(normalized, "\n")
+}
+
+// commonIndent returns the leading whitespace shared by all non-blank lines.
+func commonIndent(lines []string) string {
+	var indent string
+	first := true
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			indent = lead
+			first = false
+			continue
+		}
+
+		n := 0
+		for n < len(indent) && n < len(lead) && indent[n] == lead[n] {
+			n++
+		}
+		indent = indent[:n]
+	}
+	return indent
+}
@@ -1,9 +1,14 @@
 package message
 
+import "bytes"
+
 // messageOptions contains configuration options for message creation.
 // This struct is used internally to collect options before creating a message.
 type messageOptions struct {
-	usage usage
+	usage     usage
+	parts     []Part
+	reasoning string
+	metadata  map[string]any
 }
 
 // MessageOption is a function type that modifies message options.
@@ -32,3 +37,113 @@ func WithUsage(promptTokens int, completionTokens int, totalTokens int) MessageO
 		}
 	}
 }
+
+// WithAudio attaches an audio clip to a message for models that accept
+// audio input (e.g. gpt-4o-audio-preview or Gemini). The text content of
+// the message, if any, is sent alongside the audio. The data is copied, so
+// the caller may reuse its buffer.
+//
+// Parameters:
+//   - data: The raw audio bytes
+//   - format: The audio encoding, "wav" or "mp3"; Gemini also accepts
+//     "aac", "flac", "ogg", and "aiff"
+//
+// Example:
+//
+//	audio, _ := os.ReadFile("voice-note.wav")
+//	msg := FromUser("Transcribe this voice note and answer the question in it.",
+//	  WithAudio(audio, "wav"))
+func WithAudio(data []byte, format string) MessageOption {
+	return func(m *messageOptions) {
+		m.parts = append(m.parts, Part{
+			Type:   PartAudio,
+			Data:   bytes.Clone(data),
+			Format: format,
+		})
+	}
+}
+
+// WithDocument attaches a PDF document to a message so the model can read
+// and cite it. The document is checked against MaxDocumentSize and
+// MaxDocumentPages when the message is validated. The data is copied, so
+// the caller may reuse its buffer.
+//
+// Parameters:
+//   - data: The raw PDF bytes
+//   - name: An optional title for the document
+//
+// Example:
+//
+//	report, _ := os.ReadFile("q3-report.pdf")
+//	msg := FromUser("Summarize the key risks in this report.",
+//	  WithDocument(report, "Q3 report"))
+func WithDocument(data []byte, name string) MessageOption {
+	return func(m *messageOptions) {
+		m.parts = append(m.parts, Part{
+			Type:   PartDocument,
+			Data:   bytes.Clone(data),
+			Format: "pdf",
+			Name:   name,
+		})
+	}
+}
+
+// WithImage attaches an image to a message for models that accept image
+// input (e.g. gpt-4o, Claude, or Gemini). The text content of the message,
+// if any, is sent alongside the image. The data is copied, so the caller may
+// reuse its buffer. Large images can be downscaled with ResizeImage first.
+//
+// Parameters:
+//   - data: The raw image bytes
+//   - format: The image encoding, "jpeg", "png", "gif", or "webp"
+//
+// Example:
+//
+//	photo, _ := os.ReadFile("receipt.jpg")
+//	photo, format, err := ResizeImage(photo, 1568)
+//	if err != nil {
+//	  return err
+//	}
+//	msg := FromUser("What is the total on this receipt?", WithImage(photo, format))
+func WithImage(data []byte, format string) MessageOption {
+	return func(m *messageOptions) {
+		m.parts = append(m.parts, Part{
+			Type:   PartImage,
+			Data:   bytes.Clone(data),
+			Format: format,
+		})
+	}
+}
+
+// WithDocumentFile attaches a PDF document previously uploaded to the
+// provider's Files API, referenced by its file ID, so large or frequently
+// used documents are not re-sent with every request. File IDs are specific
+// to the provider that issued them.
+//
+// Example:
+//
+//	msg := FromUser("Which clauses limit our liability?",
+//	  WithDocumentFile("file-6F2ksmvXxt4VdoqmHRw6kL", "Master services agreement"))
+func WithDocumentFile(fileID string, name string) MessageOption {
+	return func(m *messageOptions) {
+		m.parts = append(m.parts, Part{
+			Type:   PartDocument,
+			Format: "pdf",
+			Name:   name,
+			FileID: fileID,
+		})
+	}
+}
+
+// WithReasoning records the reasoning a model produced before its answer,
+// such as the reasoning_content returned by DeepSeek reasoner models.
+//
+// Example:
+//
+//	msg := FromAssistant("The answer is 42.",
+//	  WithReasoning("The question asks for..."))
+func WithReasoning(reasoning string) MessageOption {
+	return func(m *messageOptions) {
+		m.reasoning = reasoning
+	}
+}
@@ -0,0 +1,52 @@
+package message
+
+import (
+	"sync"
+	"text/template"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// partials is the shared registry of named prompt fragments.
+// Every message rendered with Invoke can reference a registered fragment
+// with {{template "name" .}}, which makes it possible to share things like
+// output-format instructions or a safety preamble across many templates.
+var (
+	partialsMu sync.RWMutex
+	partials   = template.New("partials")
+)
+
+// RegisterPartial registers a named prompt fragment that can be referenced
+// from message content with {{template "name" .}}. Registering a fragment
+// under an existing name replaces the previous definition.
+//
+// Example:
+//
+//	err := RegisterPartial("json_only", "Respond with valid JSON only. Do not add commentary.")
+//	msg := FromSystem(`You are a classifier. {{template "json_only" .}}`)
+func RegisterPartial(name, content string) error {
+	if name == "" {
+		return errorbank.NewValidationError("name", "cannot be empty", name)
+	}
+
+	partialsMu.Lock()
+	defer partialsMu.Unlock()
+
+	if _, err := partials.New(name).Parse(content); err != nil {
+		return errorbank.NewTemplateError(name, "failed to parse partial", err)
+	}
+	return nil
+}
+
+// newRenderTemplate returns a fresh text/template with all registered
+// partials associated, ready to parse message content into.
+func newRenderTemplate() (*template.Template, error) {
+	partialsMu.RLock()
+	defer partialsMu.RUnlock()
+
+	root, err := partials.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return root.New("message"), nil
+}
@@ -0,0 +1,152 @@
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// PartType identifies the kind of non-text content attached to a message.
+type PartType string
+
+const (
+	// PartAudio is an audio clip sent as model input, such as a voice note.
+	PartAudio PartType = "audio"
+
+	// PartDocument is a document, such as a PDF, the model can read and cite.
+	PartDocument PartType = "document"
+
+	// PartImage is an image sent as model input, such as a photo or screenshot.
+	PartImage PartType = "image"
+)
+
+const (
+	// MaxDocumentSize is the largest document accepted, in bytes.
+	MaxDocumentSize = 32 << 20
+
+	// MaxDocumentPages is the largest number of pages accepted in a PDF document.
+	MaxDocumentPages = 100
+
+	// MaxImageSize is the largest image accepted, in bytes. Anthropic and
+	// Bedrock accept smaller images; use ResizeImage to fit their limits.
+	MaxImageSize = 20 << 20
+)
+
+// Part is a piece of non-text content attached to a message.
+// Parts are sent alongside the text content of the message, for providers
+// and models that accept multimodal input.
+type Part struct {
+	// Type is the kind of content.
+	Type PartType
+
+	// Data is the raw content. It is base64-encoded by providers as needed.
+	Data []byte
+
+	// Format is the encoding of the data (e.g. "wav", "mp3", or "flac" for audio,
+	// "pdf" for documents, "jpeg" or "png" for images).
+	Format string
+
+	// Name is an optional title or file name, used for documents.
+	Name string
+
+	// FileID references a file previously uploaded to the provider, such as
+	// an OpenAI or Anthropic file ID, in place of Data.
+	FileID string
+}
+
+// audioFormats lists the audio encodings accepted as model input by at
+// least one provider. Providers reject the formats they cannot read.
+var audioFormats = map[string]bool{
+	"wav":  true,
+	"mp3":  true,
+	"aac":  true,
+	"flac": true,
+	"ogg":  true,
+	"aiff": true,
+}
+
+// imageSignatures maps the image formats accepted as model input to the
+// leading bytes of their encoding.
+var imageSignatures = map[string][]byte{
+	"jpeg": []byte("\xff\xd8\xff"),
+	"png":  []byte("\x89PNG\r\n\x1a\n"),
+	"gif":  []byte("GIF8"),
+	"webp": []byte("RIFF"),
+}
+
+// Validate checks if the part is valid and returns an error if not.
+func (p Part) Validate() error {
+	if p.FileID != "" && p.Type == PartDocument {
+		if len(p.Data) > 0 {
+			return errorbank.NewValidationError("file_id", "cannot be combined with data", p.FileID)
+		}
+		return nil
+	}
+
+	if len(p.Data) == 0 {
+		return errorbank.NewValidationError("data", "cannot be empty", p.Type)
+	}
+
+	switch p.Type {
+	case PartAudio:
+		if !audioFormats[p.Format] {
+			return errorbank.NewValidationError("format", fmt.Sprintf("unsupported %s format", p.Type), p.Format)
+		}
+	case PartDocument:
+		if p.Format != "pdf" {
+			return errorbank.NewValidationError("format", fmt.Sprintf("unsupported %s format", p.Type), p.Format)
+		}
+		if len(p.Data) > MaxDocumentSize {
+			return errorbank.NewValidationError("data", fmt.Sprintf("document exceeds %d bytes", MaxDocumentSize), len(p.Data))
+		}
+		if !bytes.HasPrefix(p.Data, []byte("%PDF-")) {
+			return errorbank.NewValidationError("data", "not a PDF document", p.Name)
+		}
+		if pages := countPDFPages(p.Data); pages > MaxDocumentPages {
+			return errorbank.NewValidationError("data", fmt.Sprintf("document exceeds %d pages", MaxDocumentPages), pages)
+		}
+	case PartImage:
+		signature, ok := imageSignatures[p.Format]
+		if !ok {
+			return errorbank.NewValidationError("format", fmt.Sprintf("unsupported %s format", p.Type), p.Format)
+		}
+		if len(p.Data) > MaxImageSize {
+			return errorbank.NewValidationError("data", fmt.Sprintf("image exceeds %d bytes", MaxImageSize), len(p.Data))
+		}
+		if !bytes.HasPrefix(p.Data, signature) {
+			return errorbank.NewValidationError("data", "not a "+p.Format+" image", p.Format)
+		}
+	default:
+		return errorbank.NewValidationError("type", "invalid part type", p.Type)
+	}
+
+	return nil
+}
+
+var (
+	// pdfPagePattern matches page objects in a PDF.
+	pdfPagePattern = regexp.MustCompile(`/Type\s*/Page[^s]`)
+
+	// pdfCountPattern matches the page count of page tree nodes in a PDF.
+	pdfCountPattern = regexp.MustCompile(`/Count\s+(\d+)`)
+)
+
+// countPDFPages estimates the number of pages of a PDF without fully parsing it.
+// It counts page objects, falling back to the largest page tree count when
+// page objects are hidden in compressed object streams.
+func countPDFPages(data []byte) int {
+	if pages := len(pdfPagePattern.FindAll(data, -1)); pages > 0 {
+		return pages
+	}
+
+	pages := 0
+	for _, match := range pdfCountPattern.FindAllSubmatch(data, -1) {
+		if count, err := strconv.Atoi(string(match[1])); err == nil {
+			pages = max(pages, count)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,118 @@
+package message
+
+import (
+	"strings"
+)
+
+// reasoningOptions contains the markers used to separate reasoning from answers.
+type reasoningOptions struct {
+	markers  []string
+	openTag  string
+	closeTag string
+}
+
+// ReasoningOption is a function type that modifies how SeparateReasoning
+// recognizes reasoning.
+type ReasoningOption func(*reasoningOptions)
+
+// WithAnswerMarkers sets the markers that introduce the final answer, such
+// as "Final Answer:". Everything before the last marker is reasoning.
+//
+// Example:
+//
+//	clean := SeparateReasoning(response, WithAnswerMarkers("Answer:", "Final Answer:"))
+func WithAnswerMarkers(markers ...string) ReasoningOption {
+	return func(o *reasoningOptions) {
+		o.markers = markers
+	}
+}
+
+// WithThinkingTags sets the tags that enclose reasoning, such as the
+// <think></think> blocks emitted by open reasoning models.
+//
+// Example:
+//
+//	clean := SeparateReasoning(response, WithThinkingTags("<reasoning>", "</reasoning>"))
+func WithThinkingTags(open, close string) ReasoningOption {
+	return func(o *reasoningOptions) {
+		o.openTag = open
+		o.closeTag = close
+	}
+}
+
+// SeparateReasoning splits the reasoning a model wrote into its content from
+// the final answer. Reasoning enclosed in thinking tags (<think></think> by
+// default) is removed, and when an answer marker ("Final Answer:" by default)
+// is present only the text after the last marker is kept. The removed text is
+// preserved with GetReasoning, after any reasoning the provider returned in a
+// dedicated field, so it remains available for logging.
+//
+// Example:
+//
+//	response, err := provider.Invoke(ctx, template)
+//	answer := SeparateReasoning(response)
+//	log.Println(answer.GetReasoning())
+//	fmt.Println(answer.GetContent())
+func SeparateReasoning(m Message, options ...ReasoningOption) Message {
+	opts := reasoningOptions{
+		markers:  []string{"Final Answer:"},
+		openTag:  "<think>",
+		closeTag: "</think>",
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var reasoning []string
+	if existing := m.GetReasoning(); existing != "" {
+		reasoning = append(reasoning, existing)
+	}
+
+	content := m.GetContent()
+	if opts.openTag != "" && opts.closeTag != "" {
+		for {
+			start := strings.Index(content, opts.openTag)
+			if start < 0 {
+				break
+			}
+			end := strings.Index(content[start:], opts.closeTag)
+			if end < 0 {
+				// An unterminated block means the answer was never reached.
+				reasoning = appendTrimmed(reasoning, content[start+len(opts.openTag):])
+				content = content[:start]
+				break
+			}
+			end += start
+			reasoning = appendTrimmed(reasoning, content[start+len(opts.openTag):end])
+			content = content[:start] + content[end+len(opts.closeTag):]
+		}
+	}
+
+	cut, marker := -1, ""
+	for _, candidate := range opts.markers {
+		if i := strings.LastIndex(content, candidate); i > cut {
+			cut, marker = i, candidate
+		}
+	}
+	if cut >= 0 {
+		reasoning = appendTrimmed(reasoning, content[:cut])
+		content = content[cut+len(marker):]
+	}
+
+	return &message{
+		Role:      m.GetRole(),
+		Content:   strings.TrimSpace(content),
+		Usage:     m.GetUsage(),
+		Parts:     m.GetParts(),
+		Reasoning: strings.Join(reasoning, "\n\n"),
+		Metadata:  m.GetMetadata(),
+	}
+}
+
+// appendTrimmed appends text to list when it is not blank.
+func appendTrimmed(list []string, text string) []string {
+	if text = strings.TrimSpace(text); text != "" {
+		list = append(list, text)
+	}
+	return list
+}
@@ -0,0 +1,20 @@
+package message
+
+import (
+	"unicode/utf8"
+)
+
+// EstimateTokens returns a rough estimate of the number of tokens in the content.
+// It uses the common heuristic of about four characters per token, which is
+// close enough for budgeting and prompt-size checks without a model-specific tokenizer.
+//
+// Example:
+//
+//	tokens := EstimateTokens("What is the capital of France?") // 8
+func EstimateTokens(content string) int {
+	characters := utf8.RuneCountInString(content)
+	if characters == 0 {
+		return 0
+	}
+	return (characters + 3) / 4
+}
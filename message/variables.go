@@ -0,0 +1,162 @@
+package message
+
+import (
+	"text/template/parse"
+)
+
+// Variable describes a top-level variable read by message content.
+// Type is inferred from how the variable is used: "array" when it is
+// ranged over, "object" when its fields are accessed or it is used with
+// {{with}}, "boolean" when it is only tested with {{if}}, and "string"
+// otherwise.
+type Variable struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// variableTypeRank orders the inferred types from the weakest to the
+// strongest evidence, so repeated uses keep the most specific type.
+var variableTypeRank = map[string]int{
+	"boolean": 0,
+	"string":  1,
+	"object":  2,
+	"array":   3,
+}
+
+// Variables returns the variables read by text/template content, in order of
+// first use. When several contents are given, such as the messages of a
+// template, a variable used by more than one is listed once with the most
+// specific type. Variables read by registered partials that receive the
+// template data with {{template "name" .}} are included. Content that fails
+// to parse is skipped.
+//
+// Example:
+//
+//	vars := Variables("{{range .Items}}- {{.}}\n{{end}}Reply to {{.User.Name}}.")
+//	// [{Items array} {User object}]
+func Variables(contents ...string) []Variable {
+	c := &variableCollector{index: map[string]int{}, visited: map[string]bool{}}
+	for _, content := range contents {
+		trees, err := parse.Parse("message", content, "", "", builtinFuncs)
+		if err != nil {
+			continue
+		}
+		if tree := trees["message"]; tree != nil {
+			c.walk(tree.Root, true, "")
+		}
+	}
+	return c.variables
+}
+
+// variableCollector accumulates the variables found while walking a parse tree.
+type variableCollector struct {
+	variables []Variable
+	index     map[string]int
+	visited   map[string]bool
+}
+
+// add records a variable, upgrading its type when the new use is more specific.
+func (c *variableCollector) add(name, typ string) {
+	if i, ok := c.index[name]; ok {
+		if variableTypeRank[typ] > variableTypeRank[c.variables[i].Type] {
+			c.variables[i].Type = typ
+		}
+		return
+	}
+	c.index[name] = len(c.variables)
+	c.variables = append(c.variables, Variable{Name: name, Type: typ})
+}
+
+// walk visits node. root is false inside range and with bodies, where dot no
+// longer refers to the template data. hint is the type implied by the
+// position of the node, such as the pipeline of a range.
+func (c *variableCollector) walk(node parse.Node, root bool, hint string) {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if root {
+			c.addPath(n.Ident, hint)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			c.addPath(n.Ident[1:], hint)
+		}
+	case *parse.ChainNode:
+		c.walk(n.Node, root, "object")
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			argHint := ""
+			if len(n.Cmds) == 1 && len(cmd.Args) == 1 {
+				argHint = hint
+			}
+			for _, arg := range cmd.Args {
+				c.walk(arg, root, argHint)
+			}
+		}
+	case *parse.ActionNode:
+		c.walk(n.Pipe, root, "")
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, root, "")
+		}
+	case *parse.IfNode:
+		c.walk(n.Pipe, root, "boolean")
+		c.walk(n.List, root, "")
+		c.walk(n.ElseList, root, "")
+	case *parse.RangeNode:
+		c.walk(n.Pipe, root, "array")
+		c.walk(n.List, false, "")
+		c.walk(n.ElseList, root, "")
+	case *parse.WithNode:
+		c.walk(n.Pipe, root, "object")
+		c.walk(n.List, false, "")
+		c.walk(n.ElseList, root, "")
+	case *parse.TemplateNode:
+		c.walk(n.Pipe, root, "")
+		if root && isDotPipe(n.Pipe) {
+			c.walkPartial(n.Name)
+		}
+	}
+}
+
+// addPath records the first element of a field path. A path that goes on
+// to access fields makes the variable an object.
+func (c *variableCollector) addPath(path []string, hint string) {
+	switch {
+	case len(path) > 1:
+		hint = "object"
+	case hint == "":
+		hint = "string"
+	}
+	c.add(path[0], hint)
+}
+
+// walkPartial visits the parse tree of a registered partial once.
+func (c *variableCollector) walkPartial(name string) {
+	if c.visited[name] {
+		return
+	}
+	c.visited[name] = true
+
+	partialsMu.RLock()
+	partial := partials.Lookup(name)
+	partialsMu.RUnlock()
+	if partial == nil || partial.Tree == nil {
+		return
+	}
+	c.walk(partial.Tree.Root, true, "")
+}
+
+// isDotPipe reports whether a pipeline passes the template data unchanged.
+func isDotPipe(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	_, ok := pipe.Cmds[0].Args[0].(*parse.DotNode)
+	return ok
+}
@@ -2,6 +2,7 @@ package errorbank
 
 import (
 	"fmt"
+	"strings"
 )
 
 // MessageError represents errors that occur during message operations
@@ -56,6 +57,21 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("[Validation] field '%s': %s (value: %v)", e.Field, e.Message, e.Value)
 }
 
+// ModerationError represents content rejected by a moderation check
+type ModerationError struct {
+	// Stage is "input" for rejected prompts and "output" for rejected responses.
+	Stage      string
+	Categories []string
+}
+
+// Error returns the formatted error message
+func (e *ModerationError) Error() string {
+	if len(e.Categories) == 0 {
+		return fmt.Sprintf("[Moderation] %s flagged", e.Stage)
+	}
+	return fmt.Sprintf("[Moderation] %s flagged: %s", e.Stage, strings.Join(e.Categories, ", "))
+}
+
 // Common error constructors
 
 // NewMessageError creates a new MessageError
@@ -85,6 +101,14 @@ func NewValidationError(field, message string, value any) *ValidationError {
 	}
 }
 
+// NewModerationError creates a new ModerationError
+func NewModerationError(stage string, categories []string) *ModerationError {
+	return &ModerationError{
+		Stage:      stage,
+		Categories: categories,
+	}
+}
+
 // IsMessageError checks if an error is a MessageError
 func IsMessageError(err error) bool {
 	_, ok := err.(*MessageError)
@@ -102,3 +126,9 @@ func IsValidationError(err error) bool {
 	_, ok := err.(*ValidationError)
 	return ok
 }
+
+// IsModerationError checks if an error is a ModerationError
+func IsModerationError(err error) bool {
+	_, ok := err.(*ModerationError)
+	return ok
+}
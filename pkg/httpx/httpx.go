@@ -10,6 +10,7 @@ type Client struct {
 	httpClient     *http.Client
 	baseURL        string
 	defaultHeaders *Header
+	streamDecode   bool
 }
 
 // NewClient creates a new HTTP client
@@ -34,6 +35,15 @@ func (c *Client) WithTimeout(timeout time.Duration) *Client {
 	return c
 }
 
+// WithStreamingDecode makes successful responses decode straight from the
+// connection instead of being read into memory first. Error responses are
+// still read in full so their body can be reported. The body of a streamed
+// response can only be consumed once, by Decode or by Bytes and String.
+func (c *Client) WithStreamingDecode(enabled bool) *Client {
+	c.streamDecode = enabled
+	return c
+}
+
 // WithDefaultHeaders sets default headers for all requests
 func (c *Client) WithDefaultHeaders(headers *Header) *Client {
 	c.defaultHeaders = headers
@@ -60,6 +70,7 @@ func (c *Client) createRequest(method, url string) (*Request, error) {
 	if err != nil {
 		return nil, err
 	}
+	req.streamDecode = c.streamDecode
 
 	// Add default headers
 	if c.defaultHeaders != nil {
@@ -133,6 +144,15 @@ func (c *Client) PostForm(url string, data map[string]string) (*Response, error)
 	return req.WithForm(data).Do()
 }
 
+// PostMultipart performs a POST request with a multipart form and returns the response
+func (c *Client) PostMultipart(url string, fields map[string]string, files ...FormFile) (*Response, error) {
+	req, err := c.POST(url)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithMultipart(fields, files...).Do()
+}
+
 // Put performs a PUT request with JSON body and returns the response
 func (c *Client) Put(url string, data any) (*Response, error) {
 	req, err := c.PUT(url)
@@ -0,0 +1,36 @@
+package httpx
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which buffers are not returned
+// to the pool, so one unusually large body does not stay in memory
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds the scratch buffers used to encode request bodies and
+// read response bodies, which would otherwise be allocated and grown on
+// every request. Buffers never leave the function that took them: the bytes
+// are copied into an exactly sized slice before the buffer is returned, since
+// the transport may still read a request body after closing it
+// (golang/go#51907)
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns a buffer to the pool
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
@@ -2,19 +2,22 @@ package httpx
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/bpradana/tars/pkg/jsoncodec"
 )
 
 // Request wraps http.Request to provide convenient methods for building requests
 type Request struct {
 	*http.Request
-	client *http.Client
+	client       *http.Client
+	streamDecode bool
 }
 
 // newRequest creates a new Request instance
@@ -60,16 +63,22 @@ func (r *Request) WithBody(body io.Reader) *Request {
 	return r
 }
 
-// WithJSON sets the request body to JSON and sets Content-Type header
+// WithJSON sets the request body to JSON and sets Content-Type header.
+// The body is encoded into a pooled scratch buffer and copied out, so the
+// transport owns its own bytes.
 func (r *Request) WithJSON(data any) *Request {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := jsoncodec.NewEncoder(buf).Encode(data); err != nil {
 		// In a real implementation, you might want to handle this error differently
 		panic(fmt.Sprintf("failed to marshal JSON: %v", err))
 	}
+	// Drop the newline written by Encode so the body matches json.Marshal.
+	body := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
 
 	r.Header.Set("Content-Type", "application/json")
-	r.Body = io.NopCloser(bytes.NewBuffer(jsonData))
+	r.Body = io.NopCloser(bytes.NewReader(bytes.Clone(body)))
+	r.ContentLength = int64(len(body))
 	return r
 }
 
@@ -85,6 +94,47 @@ func (r *Request) WithForm(data map[string]string) *Request {
 	return r
 }
 
+// FormFile is a file uploaded in a multipart form
+type FormFile struct {
+	Field    string
+	Filename string
+	Reader   io.Reader
+}
+
+// WithMultipart sets the request body to a multipart form with the given
+// fields and files and sets the Content-Type header. The body is streamed
+// while the request is sent, so files are not buffered in memory; an error
+// reading a file fails the request.
+func (r *Request) WithMultipart(fields map[string]string, files ...FormFile) *Request {
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		for key, value := range fields {
+			if err := form.WriteField(key, value); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		for _, file := range files {
+			part, err := form.CreateFormFile(file.Field, file.Filename)
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(part, file.Reader); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		writer.CloseWithError(form.Close())
+	}()
+
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	r.Body = reader
+	return r
+}
+
 // WithQuery adds query parameters to the request URL
 func (r *Request) WithQuery(params map[string]string) *Request {
 	q := r.URL.Query()
@@ -112,6 +162,14 @@ func (r *Request) WithTimeout(timeout time.Duration) *Request {
 	return r
 }
 
+// WithStreamingDecode overrides the client setting for this request. When
+// enabled, a successful body is left on the connection to be read from
+// Body or by Decode instead of being buffered in memory
+func (r *Request) WithStreamingDecode(enabled bool) *Request {
+	r.streamDecode = enabled
+	return r
+}
+
 // Do executes the request and returns a Response
 func (r *Request) Do() (*Response, error) {
 	client := r.client
@@ -124,6 +182,9 @@ func (r *Request) Do() (*Response, error) {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 
+	if r.streamDecode {
+		return newStreamedResponse(resp)
+	}
 	return newResponse(resp)
 }
 
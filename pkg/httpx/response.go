@@ -1,24 +1,34 @@
 package httpx
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/bpradana/tars/pkg/jsoncodec"
 )
 
 // Response wraps http.Response to provide convenient methods for handling responses
 type Response struct {
 	*http.Response
 	body []byte
+
+	// streamed is set while the body of a streamed response is unread.
+	streamed bool
 }
 
 // newResponse creates a new Response instance
 func newResponse(resp *http.Response) (*Response, error) {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// Read into a pooled buffer and keep an exactly sized copy, instead of
+	// growing a new slice for every response.
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	body := bytes.Clone(buf.Bytes())
 
 	return &Response{
 		Response: resp,
@@ -26,6 +36,34 @@ func newResponse(resp *http.Response) (*Response, error) {
 	}, nil
 }
 
+// newStreamedResponse creates a Response that leaves a successful body on
+// the connection for Decode to read incrementally. Error responses are
+// buffered so the status is checked before any decoding.
+func newStreamedResponse(resp *http.Response) (*Response, error) {
+	if resp.StatusCode >= 400 {
+		return newResponse(resp)
+	}
+
+	return &Response{
+		Response: resp,
+		streamed: true,
+	}, nil
+}
+
+// load reads the remaining body of a streamed response into memory
+func (r *Response) load() {
+	if !r.streamed {
+		return
+	}
+	r.streamed = false
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(r.Body); err == nil {
+		r.body = bytes.Clone(buf.Bytes())
+	}
+}
+
 // StatusCode returns the HTTP status code
 func (r *Response) StatusCode() int {
 	return r.Response.StatusCode
@@ -53,21 +91,34 @@ func (r *Response) IsServerError() bool {
 
 // String returns the response body as a string
 func (r *Response) String() string {
+	r.load()
 	return string(r.body)
 }
 
 // Bytes returns the response body as bytes
 func (r *Response) Bytes() []byte {
+	r.load()
 	return r.body
 }
 
 // Decode decodes the response body into the given interface
 func (r *Response) Decode(v any) error {
+	if r.streamed {
+		r.streamed = false
+		if err := jsoncodec.NewDecoder(r.Body).Decode(v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("response body is empty")
+			}
+			return err
+		}
+		return nil
+	}
+
 	if len(r.body) == 0 {
 		return fmt.Errorf("response body is empty")
 	}
 
-	return json.Unmarshal(r.body, v)
+	return jsoncodec.Unmarshal(r.body, v)
 }
 
 // DecodeJSON is an alias for Decode for better readability
@@ -95,10 +146,21 @@ func (r *Response) Location() string {
 	return r.GetHeader("Location")
 }
 
-// Error returns an error if the response indicates an error
+// StatusError is the error returned for a response with a 4xx or 5xx status code
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the formatted error message
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Error returns a *StatusError if the response indicates an error
 func (r *Response) Error() error {
 	if r.IsError() {
-		return fmt.Errorf("HTTP %d: %s", r.StatusCode(), r.String())
+		return &StatusError{StatusCode: r.StatusCode(), Body: r.String()}
 	}
 	return nil
 }
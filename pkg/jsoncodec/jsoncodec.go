@@ -0,0 +1,113 @@
+// Package jsoncodec encodes and decodes the JSON on the request hot path
+// through a replaceable codec, so a faster library can stand in for
+// encoding/json without changing the callers.
+package jsoncodec
+
+import (
+	"encoding/json"
+	"io"
+	"sync/atomic"
+)
+
+// Codec is a JSON implementation. It must be compatible with encoding/json,
+// including struct tags and the Marshaler and Unmarshaler interfaces.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes JSON values to a stream
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads JSON values from a stream
+type Decoder interface {
+	Decode(v any) error
+}
+
+// Standard is the encoding/json codec used by default
+type Standard struct{}
+
+// Marshal calls json.Marshal
+func (Standard) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal calls json.Unmarshal
+func (Standard) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewEncoder calls json.NewEncoder
+func (Standard) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder calls json.NewDecoder
+func (Standard) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// holder wraps the codec so atomic.Value always stores the same type
+type holder struct {
+	codec Codec
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(holder{codec: Standard{}})
+}
+
+// Set replaces the codec used by the package functions. It is meant to be
+// called once at startup, before any requests are made. A nil codec
+// restores the default.
+//
+// Example:
+//
+//	type sonicCodec struct{}
+//
+//	func (sonicCodec) Marshal(v any) ([]byte, error)      { return sonic.Marshal(v) }
+//	func (sonicCodec) Unmarshal(data []byte, v any) error { return sonic.Unmarshal(data, v) }
+//	func (sonicCodec) NewEncoder(w io.Writer) jsoncodec.Encoder {
+//	  return sonic.ConfigDefault.NewEncoder(w)
+//	}
+//	func (sonicCodec) NewDecoder(r io.Reader) jsoncodec.Decoder {
+//	  return sonic.ConfigDefault.NewDecoder(r)
+//	}
+//
+//	jsoncodec.Set(sonicCodec{})
+func Set(codec Codec) {
+	if codec == nil {
+		codec = Standard{}
+	}
+	current.Store(holder{codec: codec})
+}
+
+// Get returns the codec in use
+func Get() Codec {
+	return current.Load().(holder).codec
+}
+
+// Marshal encodes v with the codec in use
+func Marshal(v any) ([]byte, error) {
+	return Get().Marshal(v)
+}
+
+// Unmarshal decodes data into v with the codec in use
+func Unmarshal(data []byte, v any) error {
+	return Get().Unmarshal(data, v)
+}
+
+// NewEncoder returns an encoder writing to w with the codec in use
+func NewEncoder(w io.Writer) Encoder {
+	return Get().NewEncoder(w)
+}
+
+// NewDecoder returns a decoder reading from r with the codec in use
+func NewDecoder(r io.Reader) Decoder {
+	return Get().NewDecoder(r)
+}
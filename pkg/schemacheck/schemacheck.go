@@ -0,0 +1,459 @@
+package schemacheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema. It supports the keywords used to
+// describe structured output: type, nullable, enum, const, properties,
+// required, additionalProperties, items, the numeric, length and size
+// bounds, pattern, allOf, anyOf, oneOf, not, and local $ref to $defs or
+// definitions. Other keywords, such as format, are ignored.
+type Schema struct {
+	root *Schema
+	defs map[string]*Schema
+
+	ref        string
+	types      []string
+	nullable   bool
+	enum       []any
+	constValue any
+	hasConst   bool
+
+	properties        map[string]*Schema
+	required          []string
+	additional        *Schema
+	forbidsAdditional bool
+	items             *Schema
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	minLength        *int
+	maxLength        *int
+	minItems         *int
+	maxItems         *int
+	pattern          *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+// ValidationError describes a value that does not match its schema
+type ValidationError struct {
+	// Path locates the value, such as "$.items[2].name"
+	Path    string
+	Message string
+}
+
+// Error returns the formatted error message
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile parses a JSON Schema document
+func Compile(schema []byte) (*Schema, error) {
+	var document map[string]any
+	if err := json.Unmarshal(schema, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return CompileMap(document)
+}
+
+// CompileMap compiles a JSON Schema that has already been decoded
+func CompileMap(schema map[string]any) (*Schema, error) {
+	root := &Schema{defs: make(map[string]*Schema)}
+	root.root = root
+
+	// Definitions are registered before they are parsed, so they can
+	// reference each other and themselves.
+	defs := make(map[string]map[string]any)
+	for _, key := range []string{"$defs", "definitions"} {
+		entries, _ := schema[key].(map[string]any)
+		for name, def := range entries {
+			def, ok := def.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("#/%s/%s: schema must be an object", key, name)
+			}
+			location := "#/" + key + "/" + name
+			defs[location] = def
+			root.defs[location] = &Schema{root: root}
+		}
+	}
+	for location, def := range defs {
+		if err := root.defs[location].parse(def, location); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := root.parse(schema, "#"); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// MustCompile is like Compile but panics if the schema is invalid
+func MustCompile(schema []byte) *Schema {
+	compiled, err := Compile(schema)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+// Validate checks a value against the schema and returns every violation
+// joined into one error. The value is either decoded JSON, raw JSON as
+// []byte or json.RawMessage, or any Go value, which is compared through
+// its JSON encoding.
+func (s *Schema) Validate(v any) error {
+	value, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	var violations []error
+	s.validate(value, "$", &violations)
+	return errors.Join(violations...)
+}
+
+// compile compiles a nested schema
+func compile(schema map[string]any, root *Schema, location string) (*Schema, error) {
+	compiled := &Schema{root: root}
+	if err := compiled.parse(schema, location); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+// parse reads the keywords of schema into s
+func (s *Schema) parse(schema map[string]any, location string) error {
+	var err error
+	sub := func(key string, value any) *Schema {
+		object, ok := value.(map[string]any)
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("%s/%s: schema must be an object", location, key))
+			return nil
+		}
+		compiled, compileErr := compile(object, s.root, location+"/"+key)
+		err = errors.Join(err, compileErr)
+		return compiled
+	}
+	subs := func(key string, value any) []*Schema {
+		list, ok := value.([]any)
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("%s/%s: must be an array of schemas", location, key))
+			return nil
+		}
+		compiled := make([]*Schema, len(list))
+		for i, item := range list {
+			compiled[i] = sub(fmt.Sprintf("%s/%d", key, i), item)
+		}
+		return compiled
+	}
+
+	for key, value := range schema {
+		switch key {
+		case "$ref":
+			ref, _ := value.(string)
+			if ref != "#" && s.root.defs[ref] == nil {
+				err = errors.Join(err, fmt.Errorf("%s: unresolved reference %q", location, ref))
+			}
+			s.ref = ref
+		case "type":
+			switch typ := value.(type) {
+			case string:
+				s.types = append(s.types, typ)
+			case []any:
+				for _, t := range typ {
+					if t, ok := t.(string); ok {
+						s.types = append(s.types, t)
+					}
+				}
+			}
+		case "nullable":
+			s.nullable, _ = value.(bool)
+		case "enum":
+			s.enum, _ = value.([]any)
+		case "const":
+			s.constValue, s.hasConst = value, true
+		case "properties":
+			properties, _ := value.(map[string]any)
+			s.properties = make(map[string]*Schema, len(properties))
+			for name, property := range properties {
+				s.properties[name] = sub("properties/"+name, property)
+			}
+		case "required":
+			names, _ := value.([]any)
+			for _, name := range names {
+				if name, ok := name.(string); ok {
+					s.required = append(s.required, name)
+				}
+			}
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				s.forbidsAdditional = !allowed
+			} else {
+				s.additional = sub(key, value)
+			}
+		case "items":
+			s.items = sub(key, value)
+		case "minimum":
+			s.minimum = number(value)
+		case "maximum":
+			s.maximum = number(value)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum = number(value)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum = number(value)
+		case "minLength":
+			s.minLength = integer(value)
+		case "maxLength":
+			s.maxLength = integer(value)
+		case "minItems":
+			s.minItems = integer(value)
+		case "maxItems":
+			s.maxItems = integer(value)
+		case "pattern":
+			pattern, _ := value.(string)
+			compiled, compileErr := regexp.Compile(pattern)
+			if compileErr != nil {
+				err = errors.Join(err, fmt.Errorf("%s/pattern: %w", location, compileErr))
+			}
+			s.pattern = compiled
+		case "allOf":
+			s.allOf = subs(key, value)
+		case "anyOf":
+			s.anyOf = subs(key, value)
+		case "oneOf":
+			s.oneOf = subs(key, value)
+		case "not":
+			s.not = sub(key, value)
+		}
+	}
+	return err
+}
+
+// validate appends the violations of value at path
+func (s *Schema) validate(value any, path string, violations *[]error) {
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil && s.nullable {
+		return
+	}
+
+	if s.ref != "" {
+		target := s.root
+		if s.ref != "#" {
+			target = s.root.defs[s.ref]
+		}
+		target.validate(value, path, violations)
+	}
+
+	if len(s.types) > 0 && !matchesType(value, s.types) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(value))
+		return
+	}
+	if len(s.enum) > 0 && !contains(s.enum, value) {
+		fail("value %v is not one of %v", value, s.enum)
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constValue, value) {
+		fail("value %v is not %v", value, s.constValue)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		s.validateObject(v, path, violations, fail)
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("expected at least %d items, got %d", *s.minItems, len(v))
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("expected at most %d items, got %d", *s.maxItems, len(v))
+		}
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			fail("expected at least %d characters, got %d", *s.minLength, length)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("expected at most %d characters, got %d", *s.maxLength, length)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("value %q does not match pattern %q", v, s.pattern.String())
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			fail("value %v is less than the minimum %v", v, *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			fail("value %v is greater than the maximum %v", v, *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+			fail("value %v must be greater than %v", v, *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+			fail("value %v must be less than %v", v, *s.exclusiveMaximum)
+		}
+	}
+
+	for _, schema := range s.allOf {
+		schema.validate(value, path, violations)
+	}
+	if len(s.anyOf) > 0 && countMatches(s.anyOf, value, path) == 0 {
+		fail("value does not match any of the allowed schemas")
+	}
+	if len(s.oneOf) > 0 {
+		if matches := countMatches(s.oneOf, value, path); matches != 1 {
+			fail("value matches %d schemas, expected exactly one", matches)
+		}
+	}
+	if s.not != nil && countMatches([]*Schema{s.not}, value, path) == 1 {
+		fail("value matches a disallowed schema")
+	}
+}
+
+// validateObject appends the violations of the properties of an object
+func (s *Schema) validateObject(object map[string]any, path string, violations *[]error, fail func(string, ...any)) {
+	for _, name := range s.required {
+		if _, ok := object[name]; !ok {
+			fail("missing required property %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property := path + "." + name
+		if schema, ok := s.properties[name]; ok {
+			schema.validate(object[name], property, violations)
+			continue
+		}
+		switch {
+		case s.additional != nil:
+			s.additional.validate(object[name], property, violations)
+		case s.forbidsAdditional:
+			fail("unexpected property %q", name)
+		}
+	}
+}
+
+// countMatches returns how many of the schemas the value satisfies
+func countMatches(schemas []*Schema, value any, path string) int {
+	matches := 0
+	for _, schema := range schemas {
+		var violations []error
+		schema.validate(value, path, &violations)
+		if len(violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+// matchesType reports whether a decoded JSON value has one of the types
+func matchesType(value any, types []string) bool {
+	actual := typeOf(value)
+	for _, typ := range types {
+		if typ == actual || (typ == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a decoded JSON value
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// contains reports whether the list contains the value
+func contains(list []any, value any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// number reads a numeric keyword
+func number(value any) *float64 {
+	n, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+	return &n
+}
+
+// integer reads a non-negative integer keyword
+func integer(value any) *int {
+	n, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+	i := int(n)
+	return &i
+}
+
+// normalize converts a value into decoded JSON
+func normalize(v any) (any, error) {
+	var data []byte
+	switch value := v.(type) {
+	case nil, bool, float64, string, []any, map[string]any:
+		return value, nil
+	case json.RawMessage:
+		data = value
+	case []byte:
+		data = value
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value: %w", err)
+		}
+		data = encoded
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,109 @@
+package textdiff
+
+import (
+	"strings"
+)
+
+// Operation describes how a line changed between two texts
+type Operation string
+
+const (
+	// Equal marks a line present in both texts
+	Equal Operation = "equal"
+
+	// Insert marks a line only present in the new text
+	Insert Operation = "insert"
+
+	// Delete marks a line only present in the old text
+	Delete Operation = "delete"
+)
+
+// Line is a single line of a diff
+type Line struct {
+	Op   Operation `json:"op"`
+	Text string    `json:"text"`
+}
+
+// Lines computes a line-based diff between two texts using the longest
+// common subsequence of their lines
+func Lines(a, b string) []Line {
+	return diff(split(a), split(b))
+}
+
+// Changed reports whether a diff contains any insertions or deletions
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders a diff in unified style, prefixing lines with " ", "+" or "-"
+func String(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		switch line.Op {
+		case Insert:
+			b.WriteString("+")
+		case Delete:
+			b.WriteString("-")
+		default:
+			b.WriteString(" ")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// split splits text into lines, treating an empty text as having no lines
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diff builds the diff of two line slices from their LCS table
+func diff(a, b []string) []Line {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Insert, Text: b[j]})
+	}
+
+	return lines
+}
@@ -0,0 +1,211 @@
+// Package webhook delivers signed JSON callbacks over HTTP.
+//
+// Every delivery carries the headers X-Webhook-Event, X-Webhook-Timestamp,
+// and X-Webhook-Signature. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of the timestamp, a dot, and the raw body, keyed with the
+// shared secret. Receivers check it with Verify.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bpradana/failsafe"
+	"github.com/bpradana/failsafe/strategies"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Header names set on every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// options contains configuration options for a Webhook.
+type options struct {
+	timeout      time.Duration
+	maxAttempts  int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+}
+
+// Option is a function type that modifies webhook options.
+type Option func(*options)
+
+// WithTimeout sets the timeout of a single delivery attempt.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxAttempts sets how many times a delivery is attempted before giving up.
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+	}
+}
+
+// WithBackoff sets the exponential backoff between delivery attempts,
+// starting at initial and doubling up to max.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(o *options) {
+		o.initialDelay = initial
+		o.maxDelay = max
+	}
+}
+
+// Webhook delivers events to a single URL.
+type Webhook struct {
+	url     string
+	secret  []byte
+	client  *httpx.Client
+	retrier *failsafe.Retrier
+}
+
+// New creates a webhook that signs deliveries with the given secret.
+// Failed deliveries are retried with exponential backoff on network errors,
+// 5xx responses, 408, and 429; other 4xx responses fail immediately.
+//
+// Example:
+//
+//	hook := webhook.New("https://example.com/hooks/tars", os.Getenv("WEBHOOK_SECRET"),
+//	  webhook.WithMaxAttempts(5),
+//	)
+//	err := hook.Deliver(ctx, "batch.completed", payload)
+func New(url, secret string, opts ...Option) *Webhook {
+	o := options{
+		timeout:      10 * time.Second,
+		maxAttempts:  3,
+		initialDelay: time.Second,
+		maxDelay:     time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Webhook{
+		url:    url,
+		secret: []byte(secret),
+		client: httpx.NewClient().WithTimeout(o.timeout),
+		retrier: failsafe.NewRetrier(
+			failsafe.WithMaxAttempts(o.maxAttempts),
+			failsafe.WithDelayStrategy(strategies.NewExponentialBackoff(o.initialDelay, o.maxDelay, 2)),
+			failsafe.WithErrorFilter(isRetryable),
+		),
+	}
+}
+
+// StatusError is returned when the receiver responds with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook receiver responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Deliver posts the payload as JSON to the webhook URL, signing the body
+// with the current time. The same timestamp and signature are used on
+// every retry, so receivers can deduplicate deliveries by signature.
+// Cancelling ctx aborts the attempt in flight and any further retries.
+func (w *Webhook) Deliver(ctx context.Context, event string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errorbank.NewMessageError("webhook_encode", "failed to encode webhook payload", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := Sign(w.secret, timestamp, body)
+
+	err = w.retrier.Retry(ctx, func() error {
+		req, err := w.client.POST(w.url)
+		if err != nil {
+			return err
+		}
+		req.Request = req.Request.WithContext(ctx)
+
+		req.
+			WithHeader("Content-Type", "application/json").
+			WithHeader(HeaderEvent, event).
+			WithHeader(HeaderTimestamp, timestamp).
+			WithHeader(HeaderSignature, signature).
+			WithBody(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+
+		resp, err := req.Do()
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.IsError() {
+			return &StatusError{StatusCode: resp.StatusCode(), Body: resp.String()}
+		}
+		return nil
+	})
+	if err != nil {
+		return errorbank.NewMessageError("webhook_delivery", "failed to deliver webhook", err)
+	}
+	return nil
+}
+
+// Sign returns the signature of a body sent at the given Unix timestamp.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a received delivery and rejects
+// deliveries older than tolerance, which protects against replays.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := webhook.Verify(secret, r.Header, body, 5*time.Minute); err != nil {
+//	  http.Error(w, "invalid signature", http.StatusUnauthorized)
+//	  return
+//	}
+func Verify(secret []byte, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp := header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errorbank.NewMessageError("webhook_verify", "webhook timestamp is missing or malformed", err)
+	}
+
+	if age := time.Since(time.Unix(seconds, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return errorbank.NewMessageError("webhook_verify", "webhook timestamp is outside the tolerance", nil)
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(HeaderSignature))) {
+		return errorbank.NewMessageError("webhook_verify", "webhook signature does not match", nil)
+	}
+	return nil
+}
+
+// isRetryable reports whether a failed delivery should be attempted again.
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	code := statusErr.StatusCode
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
@@ -0,0 +1,188 @@
+package rag
+
+import (
+	"context"
+
+	"github.com/bpradana/tars/llm"
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/retriever"
+	"github.com/bpradana/tars/template"
+)
+
+// DefaultTemplate is the prompt used by a Chain unless WithTemplate is given.
+// It receives the formatted sources as {{.Context}} and the user question
+// as {{.Question}}.
+var DefaultTemplate = template.From(
+	message.FromSystem("You are a helpful assistant. "+CitationInstruction+"\n\nSources:\n\n{{.Context}}"),
+	message.FromUser("{{.Question}}"),
+)
+
+// chainOptions contains configuration options for a Chain.
+type chainOptions struct {
+	topK             int
+	maxContextTokens int
+	reranker         retriever.Reranker
+	candidates       int
+	template         template.Template
+	formatter        func([]Source) string
+	invokeOptions    []llm.InvokeOption
+}
+
+// ChainOption is a function type that modifies chain options.
+type ChainOption func(*chainOptions)
+
+// WithTopK sets how many results are retrieved for each question.
+func WithTopK(k int) ChainOption {
+	return func(c *chainOptions) {
+		c.topK = k
+	}
+}
+
+// WithReranker reranks the retrieved results before they are stuffed into
+// the prompt. The chain retrieves candidates results and keeps the top-k
+// after reranking; when candidates is zero, four times the top-k is used.
+//
+// Example:
+//
+//	chain := NewChain(index, provider,
+//	  WithReranker(retriever.NewCohereReranker(os.Getenv("COHERE_API_KEY")), 40),
+//	)
+func WithReranker(reranker retriever.Reranker, candidates int) ChainOption {
+	return func(c *chainOptions) {
+		c.reranker = reranker
+		c.candidates = candidates
+	}
+}
+
+// WithMaxContextTokens limits the estimated tokens of the sources stuffed
+// into the prompt. Sources are kept in retrieval order until the budget is
+// spent; the rest are dropped.
+func WithMaxContextTokens(tokens int) ChainOption {
+	return func(c *chainOptions) {
+		c.maxContextTokens = tokens
+	}
+}
+
+// WithTemplate sets the prompt of the chain. The template receives the
+// formatted sources as {{.Context}} and the user question as {{.Question}}.
+//
+// Example:
+//
+//	chain := NewChain(index, provider, WithTemplate(template.From(
+//	  message.FromSystem("Answer as a support agent. "+CitationInstruction+"\n\n{{.Context}}"),
+//	  message.FromUser("{{.Question}}"),
+//	)))
+func WithTemplate(tmpl template.Template) ChainOption {
+	return func(c *chainOptions) {
+		c.template = tmpl
+	}
+}
+
+// WithFormatter sets how sources are rendered into {{.Context}}.
+// FormatContext is used by default.
+func WithFormatter(formatter func([]Source) string) ChainOption {
+	return func(c *chainOptions) {
+		c.formatter = formatter
+	}
+}
+
+// WithInvokeOptions sets the options used when invoking the provider.
+func WithInvokeOptions(options ...llm.InvokeOption) ChainOption {
+	return func(c *chainOptions) {
+		c.invokeOptions = options
+	}
+}
+
+// Chain answers questions by retrieving relevant documents, stuffing them
+// into a prompt as numbered sources, and resolving the citations of the
+// model's answer.
+type Chain struct {
+	retriever retriever.Retriever
+	provider  llm.BaseProvider
+	options   chainOptions
+}
+
+// Result is the answer of a Chain together with everything that produced it.
+type Result struct {
+	Answer
+
+	// Context lists the sources given to the model, cited or not.
+	Context []Source
+
+	// Message is the provider response, including its usage.
+	Message message.Message
+}
+
+// NewChain creates a RAG chain over a retriever and a provider.
+// By default it retrieves 4 results and uses DefaultTemplate.
+//
+// Example:
+//
+//	chain := NewChain(index, provider, WithTopK(5), WithMaxContextTokens(2000))
+//	result, err := chain.Run(ctx, "How long do refunds take?")
+//	fmt.Println(result.Text)
+//	for _, source := range result.Sources {
+//	  fmt.Println(source.ID, source.Metadata["url"])
+//	}
+func NewChain(r retriever.Retriever, provider llm.BaseProvider, options ...ChainOption) *Chain {
+	opts := chainOptions{
+		topK:      4,
+		template:  DefaultTemplate,
+		formatter: FormatContext,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.reranker != nil {
+		r = retriever.NewReranked(r, opts.reranker, opts.candidates)
+	}
+
+	return &Chain{
+		retriever: r,
+		provider:  provider,
+		options:   opts,
+	}
+}
+
+// Run answers a question.
+func (c *Chain) Run(ctx context.Context, question string) (Result, error) {
+	results, err := c.retriever.Retrieve(ctx, question, c.options.topK)
+	if err != nil {
+		return Result{}, errorbank.NewMessageError("retrieve", "failed to retrieve context", err)
+	}
+
+	sources := c.budget(NewSources(results))
+	prompt := c.options.template.Invoke(map[string]any{
+		"Context":  c.options.formatter(sources),
+		"Question": question,
+	})
+
+	response, err := c.provider.Invoke(ctx, prompt, c.options.invokeOptions...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Answer:  ParseCitations(response.GetContent(), sources),
+		Context: sources,
+		Message: response,
+	}, nil
+}
+
+// budget drops the sources that do not fit the context token budget.
+func (c *Chain) budget(sources []Source) []Source {
+	if c.options.maxContextTokens <= 0 {
+		return sources
+	}
+
+	tokens := 0
+	for i, source := range sources {
+		tokens += message.EstimateTokens(c.options.formatter([]Source{source}))
+		if tokens > c.options.maxContextTokens {
+			return sources[:i]
+		}
+	}
+	return sources
+}
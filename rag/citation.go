@@ -0,0 +1,121 @@
+package rag
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bpradana/tars/retriever"
+)
+
+// CitationInstruction tells the model how to cite the numbered sources
+// produced by FormatContext. It is meant to be appended to the system prompt.
+const CitationInstruction = "Answer using only the numbered sources provided. " +
+	"Cite every claim with the number of its source in square brackets, for example [1] or [2][3]. " +
+	"If the sources do not contain the answer, say so."
+
+// Source is a retrieved chunk injected into the prompt under a stable citation ID.
+type Source struct {
+	// ID is the citation number the model uses to refer to this source.
+	ID int
+
+	retriever.Result
+}
+
+// Citation is a reference to a source found in the model's answer.
+type Citation struct {
+	// Source is the cited source.
+	Source Source
+
+	// Start and End are the byte offsets of the citation marker in the answer.
+	Start int
+	End   int
+}
+
+// Answer is a model answer together with the sources it cites.
+type Answer struct {
+	// Text is the raw answer returned by the model.
+	Text string
+
+	// Citations lists every citation marker in order of appearance.
+	Citations []Citation
+
+	// Sources lists each cited source once, in order of first citation.
+	Sources []Source
+}
+
+// citationPattern matches citation markers such as [1] or [12].
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// NewSources assigns citation IDs to retrieved results, starting at 1,
+// in the order they were retrieved.
+func NewSources(results []retriever.Result) []Source {
+	sources := make([]Source, len(results))
+	for i, result := range results {
+		sources[i] = Source{
+			ID:     i + 1,
+			Result: result,
+		}
+	}
+	return sources
+}
+
+// FormatContext renders the sources as a numbered list suitable for
+// stuffing into a prompt.
+//
+// Example output:
+//
+//	[1] Refunds are processed within 5 business days.
+//
+//	[2] Contact support to cancel a subscription.
+func FormatContext(sources []Source) string {
+	parts := make([]string, len(sources))
+	for i, source := range sources {
+		parts[i] = fmt.Sprintf("[%d] %s", source.ID, strings.TrimSpace(source.Content))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ParseCitations extracts citation markers from the answer and resolves
+// them against the sources. Markers that do not refer to a known source are ignored.
+//
+// Example:
+//
+//	answer := ParseCitations("Refunds take 5 days [1].", sources)
+//	for _, source := range answer.Sources {
+//	  fmt.Println(source.ID, source.Metadata["url"])
+//	}
+func ParseCitations(text string, sources []Source) Answer {
+	byID := make(map[int]Source, len(sources))
+	for _, source := range sources {
+		byID[source.ID] = source
+	}
+
+	answer := Answer{Text: text}
+	seen := make(map[int]bool)
+	for _, match := range citationPattern.FindAllStringSubmatchIndex(text, -1) {
+		id, err := strconv.Atoi(text[match[2]:match[3]])
+		if err != nil {
+			continue
+		}
+
+		source, ok := byID[id]
+		if !ok {
+			continue
+		}
+
+		answer.Citations = append(answer.Citations, Citation{
+			Source: source,
+			Start:  match[0],
+			End:    match[1],
+		})
+
+		if !seen[id] {
+			seen[id] = true
+			answer.Sources = append(answer.Sources, source)
+		}
+	}
+
+	return answer
+}
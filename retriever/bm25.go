@@ -0,0 +1,154 @@
+package retriever
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BM25 is an in-memory keyword retriever backed by an inverted index
+// and scored with the Okapi BM25 ranking function.
+// It handles short keyword queries well, which is where vector search is weakest.
+type BM25 struct {
+	mu        sync.RWMutex
+	k1        float64
+	b         float64
+	documents map[string]Document
+	lengths   map[string]int
+	postings  map[string]map[string]int
+	total     int
+}
+
+// NewBM25 creates an empty BM25 index with the standard parameters (k1=1.2, b=0.75).
+//
+// Example:
+//
+//	index := NewBM25()
+//	err := index.Add(ctx,
+//	  Document{ID: "1", Content: "How to reset your password"},
+//	  Document{ID: "2", Content: "Billing and invoices"},
+//	)
+//	results, err := index.Retrieve(ctx, "password reset", 5)
+func NewBM25() *BM25 {
+	return &BM25{
+		k1:        1.2,
+		b:         0.75,
+		documents: make(map[string]Document),
+		lengths:   make(map[string]int),
+		postings:  make(map[string]map[string]int),
+	}
+}
+
+// Add indexes the given documents, replacing documents with the same ID.
+// It never fails; the error is returned to satisfy the Index interface.
+func (i *BM25) Add(ctx context.Context, documents ...Document) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, document := range documents {
+		i.remove(document.ID)
+
+		terms := tokenize(document.Content)
+		for _, term := range terms {
+			if i.postings[term] == nil {
+				i.postings[term] = make(map[string]int)
+			}
+			i.postings[term][document.ID]++
+		}
+
+		i.documents[document.ID] = document
+		i.lengths[document.ID] = len(terms)
+		i.total += len(terms)
+	}
+	return nil
+}
+
+// Remove deletes the documents with the given IDs from the index.
+func (i *BM25) Remove(ids ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		i.remove(id)
+	}
+}
+
+// remove deletes a document from the index. The caller must hold the lock.
+func (i *BM25) remove(id string) {
+	document, ok := i.documents[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range tokenize(document.Content) {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+
+	i.total -= i.lengths[id]
+	delete(i.documents, id)
+	delete(i.lengths, id)
+}
+
+// Retrieve returns the k documents with the highest BM25 score for the query.
+func (i *BM25) Retrieve(ctx context.Context, query string, k int) ([]Result, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if len(i.documents) == 0 {
+		return nil, nil
+	}
+
+	count := float64(len(i.documents))
+	averageLength := float64(i.total) / count
+
+	scores := make(map[string]float64)
+	for _, term := range tokenize(query) {
+		postings := i.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		frequency := float64(len(postings))
+		idf := math.Log(1 + (count-frequency+0.5)/(frequency+0.5))
+		for id, termFrequency := range postings {
+			tf := float64(termFrequency)
+			norm := i.k1 * (1 - i.b + i.b*float64(i.lengths[id])/averageLength)
+			scores[id] += idf * tf * (i.k1 + 1) / (tf + norm)
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{Document: i.documents[id], Score: score})
+	}
+	return topK(results, k), nil
+}
+
+// tokenize lowercases text and splits it into letter/digit terms.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// topK sorts results by decreasing score and keeps the first k.
+// Ties are broken by document ID so the order is deterministic.
+func topK(results []Result, k int) []Result {
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].ID < results[b].ID
+	})
+
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
@@ -0,0 +1,97 @@
+package retriever
+
+import (
+	"context"
+)
+
+// hybridOptions contains configuration options for a Hybrid retriever.
+type hybridOptions struct {
+	rankConstant float64
+	candidates   int
+}
+
+// HybridOption is a function type that modifies hybrid retriever options.
+type HybridOption func(*hybridOptions)
+
+// WithRankConstant sets the k constant of reciprocal-rank fusion.
+// Larger values flatten the contribution of top-ranked results. The default is 60.
+//
+// Example:
+//
+//	hybrid := NewHybrid([]Retriever{keyword, semantic}, WithRankConstant(30))
+func WithRankConstant(rankConstant float64) HybridOption {
+	return func(h *hybridOptions) {
+		h.rankConstant = rankConstant
+	}
+}
+
+// WithCandidates sets how many results are requested from each underlying
+// retriever before fusion. When zero, four times the requested k is used.
+//
+// Example:
+//
+//	hybrid := NewHybrid([]Retriever{keyword, semantic}, WithCandidates(50))
+func WithCandidates(candidates int) HybridOption {
+	return func(h *hybridOptions) {
+		h.candidates = candidates
+	}
+}
+
+// Hybrid combines several retrievers (typically BM25 and Vector) using
+// reciprocal-rank fusion, so documents ranked highly by any of them surface
+// without having to calibrate their incomparable scores.
+type Hybrid struct {
+	retrievers []Retriever
+	options    hybridOptions
+}
+
+// NewHybrid creates a retriever that fuses the results of the given retrievers.
+//
+// Example:
+//
+//	keyword := NewBM25()
+//	semantic := NewVector(embedder)
+//	hybrid := NewHybrid([]Retriever{keyword, semantic})
+//	results, err := hybrid.Retrieve(ctx, "error E1042", 5)
+func NewHybrid(retrievers []Retriever, options ...HybridOption) *Hybrid {
+	opts := hybridOptions{
+		rankConstant: 60,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &Hybrid{
+		retrievers: retrievers,
+		options:    opts,
+	}
+}
+
+// Retrieve queries every retriever and returns the k documents with the
+// highest fused score. The score of a result is its reciprocal-rank fusion score.
+func (h *Hybrid) Retrieve(ctx context.Context, query string, k int) ([]Result, error) {
+	candidates := h.options.candidates
+	if candidates <= 0 {
+		candidates = 4 * k
+	}
+
+	documents := make(map[string]Document)
+	scores := make(map[string]float64)
+	for _, retriever := range h.retrievers {
+		results, err := retriever.Retrieve(ctx, query, candidates)
+		if err != nil {
+			return nil, err
+		}
+
+		for rank, result := range results {
+			documents[result.ID] = result.Document
+			scores[result.ID] += 1 / (h.options.rankConstant + float64(rank+1))
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{Document: documents[id], Score: score})
+	}
+	return topK(results, k), nil
+}
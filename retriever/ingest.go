@@ -0,0 +1,187 @@
+package retriever
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// Chunker splits the content of a source document into chunks that are indexed separately.
+type Chunker func(content string) []string
+
+// ingesterOptions contains configuration options for an Ingester.
+type ingesterOptions struct {
+	chunker Chunker
+}
+
+// IngesterOption is a function type that modifies ingester options.
+type IngesterOption func(*ingesterOptions)
+
+// WithChunker sets how source documents are split before indexing.
+// By default each source document is indexed as a single chunk.
+//
+// Example:
+//
+//	ingester := NewIngester(index, WithChunker(func(content string) []string {
+//	  return strings.Split(content, "\n\n")
+//	}))
+func WithChunker(chunker Chunker) IngesterOption {
+	return func(i *ingesterOptions) {
+		i.chunker = chunker
+	}
+}
+
+// IngestStats summarizes the outcome of an Ingest call.
+type IngestStats struct {
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+// sourceState remembers what was indexed for a source document.
+type sourceState struct {
+	hash     string
+	chunkIDs []string
+}
+
+// Ingester keeps an Index in sync with a set of source documents.
+// It hashes every source document and only re-chunks and re-indexes
+// (and therefore re-embeds) the documents whose content or metadata changed.
+type Ingester struct {
+	mu      sync.Mutex
+	index   Index
+	options ingesterOptions
+	sources map[string]sourceState
+}
+
+// NewIngester creates an ingester that writes chunks into the given index.
+//
+// Example:
+//
+//	ingester := NewIngester(NewVector(embedder))
+//	stats, err := ingester.Ingest(ctx, Document{
+//	  ID:       "handbook.md",
+//	  Content:  handbook,
+//	  Metadata: map[string]any{"url": "https://example.com/handbook"},
+//	})
+func NewIngester(index Index, options ...IngesterOption) *Ingester {
+	opts := ingesterOptions{
+		chunker: func(content string) []string {
+			return []string{content}
+		},
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &Ingester{
+		index:   index,
+		options: opts,
+		sources: make(map[string]sourceState),
+	}
+}
+
+// Ingest indexes the given source documents. Documents whose hash matches
+// the previously ingested version are skipped; changed documents have
+// their old chunks removed before the new chunks are added.
+//
+// Each chunk gets the ID "<source id>#<n>" and carries the source metadata
+// plus "source_id", "chunk", and "content_hash" entries.
+func (i *Ingester) Ingest(ctx context.Context, documents ...Document) (IngestStats, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var stats IngestStats
+	for _, document := range documents {
+		if document.ID == "" {
+			return stats, errorbank.NewValidationError("id", "cannot be empty", document.ID)
+		}
+
+		hash, err := hashDocument(document)
+		if err != nil {
+			return stats, err
+		}
+
+		previous, exists := i.sources[document.ID]
+		if exists && previous.hash == hash {
+			stats.Unchanged++
+			continue
+		}
+
+		chunks := i.newChunks(document, hash)
+		if exists {
+			i.index.Remove(previous.chunkIDs...)
+		}
+		if err := i.index.Add(ctx, chunks...); err != nil {
+			delete(i.sources, document.ID)
+			return stats, errorbank.NewMessageError("ingest", fmt.Sprintf("failed to index document %s", document.ID), err)
+		}
+
+		chunkIDs := make([]string, len(chunks))
+		for n, chunk := range chunks {
+			chunkIDs[n] = chunk.ID
+		}
+		i.sources[document.ID] = sourceState{hash: hash, chunkIDs: chunkIDs}
+
+		if exists {
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+	}
+
+	return stats, nil
+}
+
+// Delete removes the given source documents and all of their chunks from the index.
+func (i *Ingester) Delete(ids ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		if state, ok := i.sources[id]; ok {
+			i.index.Remove(state.chunkIDs...)
+			delete(i.sources, id)
+		}
+	}
+}
+
+// newChunks splits a source document into indexable chunks.
+func (i *Ingester) newChunks(document Document, hash string) []Document {
+	contents := i.options.chunker(document.Content)
+	chunks := make([]Document, 0, len(contents))
+	for n, content := range contents {
+		metadata := make(map[string]any, len(document.Metadata)+3)
+		maps.Copy(metadata, document.Metadata)
+		metadata["source_id"] = document.ID
+		metadata["chunk"] = n
+		metadata["content_hash"] = hash
+
+		chunks = append(chunks, Document{
+			ID:       fmt.Sprintf("%s#%d", document.ID, n),
+			Content:  content,
+			Metadata: metadata,
+		})
+	}
+	return chunks
+}
+
+// hashDocument returns a SHA-256 hash of the content and metadata of a document.
+func hashDocument(document Document) (string, error) {
+	metadata, err := json.Marshal(document.Metadata)
+	if err != nil {
+		return "", errorbank.NewMessageError("ingest", "failed to hash document metadata", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(document.Content))
+	hash.Write([]byte{0})
+	hash.Write(metadata)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,223 @@
+package retriever
+
+import (
+	"context"
+	"time"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/pkg/httpx"
+)
+
+// Reranker reorders candidate documents by their relevance to a query,
+// typically with a cross-encoder model that is more accurate, but slower,
+// than the retriever that produced the candidates. Implementations return
+// the documents ordered by decreasing relevance score.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, documents []Document) ([]Result, error)
+}
+
+// Reranked is a retriever that fetches candidates from another retriever and
+// reorders them with a Reranker.
+type Reranked struct {
+	retriever  Retriever
+	reranker   Reranker
+	candidates int
+}
+
+// NewReranked creates a retriever that reranks the results of r. It requests
+// candidates results from r and returns the k best after reranking. When
+// candidates is zero, four times the requested k is used.
+//
+// Example:
+//
+//	reranked := NewReranked(hybrid, NewCohereReranker(os.Getenv("COHERE_API_KEY")), 50)
+//	results, err := reranked.Retrieve(ctx, "how do I get a refund?", 5)
+func NewReranked(r Retriever, reranker Reranker, candidates int) *Reranked {
+	return &Reranked{
+		retriever:  r,
+		reranker:   reranker,
+		candidates: candidates,
+	}
+}
+
+// Retrieve returns the k candidates ranked highest by the reranker.
+// The score of a result is the relevance score of the reranker.
+func (r *Reranked) Retrieve(ctx context.Context, query string, k int) ([]Result, error) {
+	candidates := r.candidates
+	if candidates <= 0 {
+		candidates = 4 * k
+	}
+
+	results, err := r.retriever.Retrieve(ctx, query, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	documents := make([]Document, len(results))
+	for i, result := range results {
+		documents[i] = result.Document
+	}
+
+	reranked, err := r.reranker.Rerank(ctx, query, documents)
+	if err != nil {
+		return nil, errorbank.NewMessageError("rerank", "failed to rerank results", err)
+	}
+	return topK(reranked, k), nil
+}
+
+// rerankerOptions contains configuration options for an API reranker.
+type rerankerOptions struct {
+	baseURL string
+	model   string
+	timeout time.Duration
+}
+
+// RerankerOption is a function type that modifies reranker options.
+type RerankerOption func(*rerankerOptions)
+
+// WithRerankModel sets the rerank model.
+//
+// Example:
+//
+//	reranker := NewJinaReranker(key, WithRerankModel("jina-reranker-m0"))
+func WithRerankModel(model string) RerankerOption {
+	return func(r *rerankerOptions) {
+		r.model = model
+	}
+}
+
+// WithRerankBaseURL sets the base URL of the rerank API, e.g. for a proxy.
+func WithRerankBaseURL(baseURL string) RerankerOption {
+	return func(r *rerankerOptions) {
+		r.baseURL = baseURL
+	}
+}
+
+// WithRerankTimeout sets the HTTP timeout of rerank requests.
+func WithRerankTimeout(timeout time.Duration) RerankerOption {
+	return func(r *rerankerOptions) {
+		r.timeout = timeout
+	}
+}
+
+// apiReranker calls a rerank endpoint that follows the request and response
+// format shared by Cohere and Jina AI.
+type apiReranker struct {
+	name   string
+	path   string
+	model  string
+	apiKey string
+	client *httpx.Client
+}
+
+// rerankRequest is the body of a rerank request.
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n"`
+}
+
+// rerankResponse is the body of a rerank response.
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// NewCohereReranker creates a reranker backed by Cohere Rerank.
+// The model defaults to rerank-v3.5.
+//
+// Example:
+//
+//	reranker := NewCohereReranker(os.Getenv("COHERE_API_KEY"))
+func NewCohereReranker(apiKey string, options ...RerankerOption) Reranker {
+	return newAPIReranker("Cohere", "/v2/rerank", apiKey, rerankerOptions{
+		baseURL: "https://api.cohere.com",
+		model:   "rerank-v3.5",
+		timeout: 10 * time.Second,
+	}, options)
+}
+
+// NewJinaReranker creates a reranker backed by the Jina AI Reranker API.
+// The model defaults to jina-reranker-v2-base-multilingual.
+//
+// Example:
+//
+//	reranker := NewJinaReranker(os.Getenv("JINA_API_KEY"))
+func NewJinaReranker(apiKey string, options ...RerankerOption) Reranker {
+	return newAPIReranker("Jina", "/v1/rerank", apiKey, rerankerOptions{
+		baseURL: "https://api.jina.ai",
+		model:   "jina-reranker-v2-base-multilingual",
+		timeout: 10 * time.Second,
+	}, options)
+}
+
+// newAPIReranker applies the options and creates the HTTP client of a reranker.
+func newAPIReranker(name, path, apiKey string, opts rerankerOptions, options []RerankerOption) *apiReranker {
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &apiReranker{
+		name:   name,
+		path:   path,
+		model:  opts.model,
+		apiKey: apiKey,
+		client: httpx.NewClient().
+			WithBaseURL(opts.baseURL).
+			WithDefaultHeaders(httpx.NewHeader().Bearer(apiKey)).
+			WithTimeout(opts.timeout),
+	}
+}
+
+// Rerank scores every document against the query.
+func (r *apiReranker) Rerank(ctx context.Context, query string, documents []Document) ([]Result, error) {
+	if r.apiKey == "" {
+		return nil, errorbank.NewValidationError("api_key", r.name+" API key is required", "")
+	}
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	request := rerankRequest{
+		Model:     r.model,
+		Query:     query,
+		Documents: make([]string, len(documents)),
+		TopN:      len(documents),
+	}
+	for i, document := range documents {
+		request.Documents[i] = document.Content
+	}
+
+	resp, err := r.client.Post(r.path, request)
+	if err != nil {
+		return nil, errorbank.NewMessageError("http_request", "failed to create request", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, errorbank.NewMessageError("http_status", r.name+" returned an error response", resp.Error())
+	}
+
+	var result rerankResponse
+	if err := resp.Decode(&result); err != nil {
+		return nil, errorbank.NewMessageError("response_decode", "failed to decode response", err)
+	}
+
+	results := make([]Result, 0, len(result.Results))
+	for _, item := range result.Results {
+		if item.Index < 0 || item.Index >= len(documents) {
+			continue
+		}
+		results = append(results, Result{
+			Document: documents[item.Index],
+			Score:    item.RelevanceScore,
+		})
+	}
+	return topK(results, 0), nil
+}
@@ -0,0 +1,44 @@
+package retriever
+
+import (
+	"context"
+)
+
+// Document is a unit of retrievable content.
+type Document struct {
+	// ID uniquely identifies the document within a retriever.
+	ID string
+
+	// Content is the text that is searched and returned to the caller.
+	Content string
+
+	// Metadata carries arbitrary information about the document (source, title, etc.).
+	Metadata map[string]any
+}
+
+// Result is a document returned by a retriever together with its relevance score.
+// Scores are only comparable between results of the same retriever.
+type Result struct {
+	Document
+	Score float64
+}
+
+// Retriever defines the interface for finding documents relevant to a query.
+// Implementations return at most k results ordered by decreasing relevance.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string, k int) ([]Result, error)
+}
+
+// Index is a retriever whose documents can be added and removed.
+// Both BM25 and Vector implement it.
+type Index interface {
+	Retriever
+	Add(ctx context.Context, documents ...Document) error
+	Remove(ids ...string)
+}
+
+// Embedder turns texts into embedding vectors.
+// It is implemented by the caller on top of their embedding model of choice.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
@@ -0,0 +1,114 @@
+package retriever
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// Vector is an in-memory semantic retriever that ranks documents by the
+// cosine similarity between their embeddings and the query embedding.
+type Vector struct {
+	mu         sync.RWMutex
+	embedder   Embedder
+	documents  map[string]Document
+	embeddings map[string][]float64
+}
+
+// NewVector creates an empty vector retriever using the given embedder.
+//
+// Example:
+//
+//	index := NewVector(embedder)
+//	if err := index.Add(ctx, documents...); err != nil {
+//	  log.Fatal(err)
+//	}
+//	results, err := index.Retrieve(ctx, "how do I get a refund?", 5)
+func NewVector(embedder Embedder) *Vector {
+	return &Vector{
+		embedder:   embedder,
+		documents:  make(map[string]Document),
+		embeddings: make(map[string][]float64),
+	}
+}
+
+// Add embeds and indexes the given documents, replacing documents with the same ID.
+func (v *Vector) Add(ctx context.Context, documents ...Document) error {
+	texts := make([]string, len(documents))
+	for i, document := range documents {
+		texts[i] = document.Content
+	}
+
+	embeddings, err := v.embedder.Embed(ctx, texts)
+	if err != nil {
+		return errorbank.NewMessageError("embed", "failed to embed documents", err)
+	}
+	if len(embeddings) != len(documents) {
+		return errorbank.NewMessageError("embed", "embedder returned an unexpected number of vectors", nil)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for i, document := range documents {
+		v.documents[document.ID] = document
+		v.embeddings[document.ID] = embeddings[i]
+	}
+	return nil
+}
+
+// Remove deletes the documents with the given IDs.
+func (v *Vector) Remove(ids ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, id := range ids {
+		delete(v.documents, id)
+		delete(v.embeddings, id)
+	}
+}
+
+// Retrieve returns the k documents most similar to the query.
+func (v *Vector) Retrieve(ctx context.Context, query string, k int) ([]Result, error) {
+	embeddings, err := v.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, errorbank.NewMessageError("embed", "failed to embed query", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, errorbank.NewMessageError("embed", "embedder returned an unexpected number of vectors", nil)
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	results := make([]Result, 0, len(v.documents))
+	for id, document := range v.documents {
+		results = append(results, Result{
+			Document: document,
+			Score:    Cosine(embeddings[0], v.embeddings[id]),
+		})
+	}
+	return topK(results, k), nil
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 when either is empty
+// or their dimensions differ.
+func Cosine(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
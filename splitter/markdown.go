@@ -0,0 +1,131 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/bpradana/tars/retriever"
+)
+
+// Markdown creates a splitter that splits Markdown on its headers, so each
+// chunk covers a single section. Every chunk starts with the headers of the
+// sections it is nested in, which keeps the context of the section for
+// retrieval. Sections larger than the chunk size are split further with the
+// recursive splitter, and headers inside fenced code blocks are ignored.
+// Chunks are 1000 characters with an overlap of 200 by default.
+//
+// Example:
+//
+//	chunker := splitter.Markdown(splitter.WithChunkSize(800))
+//	chunks := chunker(readme)
+func Markdown(opts ...Option) retriever.Chunker {
+	o := newOptions(options{
+		chunkSize:  1000,
+		overlap:    200,
+		separators: []string{"\n\n", "\n", " ", ""},
+		length:     Characters,
+	}, opts)
+
+	return func(content string) []string {
+		var chunks []string
+		for _, section := range markdownSections(content) {
+			body := strings.TrimSpace(section.body)
+			if body == "" {
+				continue
+			}
+
+			prefix := ""
+			if len(section.headers) > 0 {
+				prefix = strings.Join(section.headers, "\n") + "\n\n"
+			}
+
+			// Leave room for the headers repeated on every chunk.
+			bodyOptions := o
+			bodyOptions.chunkSize = max(o.chunkSize-o.length(prefix), 1)
+			if bodyOptions.overlap >= bodyOptions.chunkSize {
+				bodyOptions.overlap = 0
+			}
+			for _, chunk := range bodyOptions.split(body, o.separators) {
+				chunks = append(chunks, prefix+chunk)
+			}
+		}
+		return chunks
+	}
+}
+
+// markdownSection is the text under a header, with the headers of every
+// enclosing section.
+type markdownSection struct {
+	headers []string
+	body    string
+}
+
+// markdownSections splits Markdown into sections at ATX headers (# to ######).
+func markdownSections(content string) []markdownSection {
+	var (
+		sections []markdownSection
+		headers  []string
+		levels   []int
+		body     strings.Builder
+		fence    string
+	)
+
+	flush := func() {
+		sections = append(sections, markdownSection{
+			headers: append([]string(nil), headers...),
+			body:    body.String(),
+		})
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if marker := fenceMarker(trimmed); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+		}
+
+		level := headerLevel(trimmed)
+		if fence != "" || level == 0 {
+			body.WriteString(line)
+			continue
+		}
+
+		flush()
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels = levels[:len(levels)-1]
+			headers = headers[:len(headers)-1]
+		}
+		levels = append(levels, level)
+		headers = append(headers, trimmed)
+	}
+	flush()
+
+	return sections
+}
+
+// headerLevel returns the level of an ATX header line, or 0 for other lines.
+func headerLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0
+	}
+	return level
+}
+
+// fenceMarker returns the fence that opens or closes a code block on the
+// line, or an empty string for other lines.
+func fenceMarker(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+// Package splitter splits documents into chunks for embedding and retrieval.
+// Every splitter returns a retriever.Chunker, so it can be passed directly
+// to retriever.WithChunker.
+package splitter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/retriever"
+)
+
+// LengthFunc measures the size of a chunk.
+type LengthFunc func(text string) int
+
+// Characters measures text in characters (runes).
+func Characters(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// Tokens measures text in estimated tokens, see message.EstimateTokens.
+func Tokens(text string) int {
+	return message.EstimateTokens(text)
+}
+
+// options contains configuration options for a splitter.
+type options struct {
+	chunkSize  int
+	overlap    int
+	separators []string
+	length     LengthFunc
+}
+
+// Option is a function type that modifies splitter options.
+type Option func(*options)
+
+// WithChunkSize sets the maximum size of a chunk, measured by the length function.
+func WithChunkSize(chunkSize int) Option {
+	return func(o *options) {
+		o.chunkSize = chunkSize
+	}
+}
+
+// WithOverlap sets how much trailing text of a chunk is repeated at the
+// start of the next one, which keeps context that straddles a boundary.
+// An overlap that is not smaller than the chunk size is ignored.
+func WithOverlap(overlap int) Option {
+	return func(o *options) {
+		o.overlap = overlap
+	}
+}
+
+// WithSeparators sets the separators tried in order when splitting text.
+// Text is split on the first separator that occurs in it, and pieces that
+// are still too large are split with the next. The empty separator splits
+// between characters.
+func WithSeparators(separators ...string) Option {
+	return func(o *options) {
+		o.separators = separators
+	}
+}
+
+// WithLength sets how chunk sizes are measured, e.g. Characters or Tokens.
+func WithLength(length LengthFunc) Option {
+	return func(o *options) {
+		o.length = length
+	}
+}
+
+// newOptions applies the options over the defaults of a splitter.
+func newOptions(defaults options, opts []Option) options {
+	for _, opt := range opts {
+		opt(&defaults)
+	}
+	if defaults.overlap >= defaults.chunkSize || defaults.overlap < 0 {
+		defaults.overlap = 0
+	}
+	return defaults
+}
+
+// Recursive creates a splitter that splits on paragraphs, then lines, then
+// words, then characters, until every chunk fits the chunk size, and merges
+// adjacent pieces back together up to that size. Chunks are 1000 characters
+// with an overlap of 200 by default.
+//
+// Example:
+//
+//	ingester := retriever.NewIngester(index, retriever.WithChunker(
+//	  splitter.Recursive(splitter.WithChunkSize(500), splitter.WithOverlap(50)),
+//	))
+func Recursive(opts ...Option) retriever.Chunker {
+	o := newOptions(options{
+		chunkSize:  1000,
+		overlap:    200,
+		separators: []string{"\n\n", "\n", " ", ""},
+		length:     Characters,
+	}, opts)
+
+	return func(content string) []string {
+		return o.split(content, o.separators)
+	}
+}
+
+// TokenAware creates a recursive splitter whose chunk size and overlap are
+// measured in estimated tokens, so chunks fit the input limit of an
+// embedding model. Chunks are 256 tokens with an overlap of 32 by default.
+//
+// Example:
+//
+//	chunker := splitter.TokenAware(splitter.WithChunkSize(512))
+func TokenAware(opts ...Option) retriever.Chunker {
+	return Recursive(append([]Option{
+		WithChunkSize(256),
+		WithOverlap(32),
+		WithLength(Tokens),
+	}, opts...)...)
+}
+
+// split recursively splits text with the given separators.
+func (o options) split(text string, separators []string) []string {
+	separator := ""
+	var remaining []string
+	for i, candidate := range separators {
+		if candidate == "" || strings.Contains(text, candidate) {
+			separator = candidate
+			remaining = separators[i+1:]
+			break
+		}
+	}
+
+	var chunks, fitting []string
+	for _, piece := range strings.Split(text, separator) {
+		if o.length(piece) <= o.chunkSize {
+			fitting = append(fitting, piece)
+			continue
+		}
+
+		chunks = append(chunks, o.merge(fitting, separator)...)
+		fitting = nil
+		if len(remaining) > 0 {
+			chunks = append(chunks, o.split(piece, remaining)...)
+		} else if piece = strings.TrimSpace(piece); piece != "" {
+			chunks = append(chunks, piece)
+		}
+	}
+	return append(chunks, o.merge(fitting, separator)...)
+}
+
+// merge joins consecutive pieces into chunks of at most the chunk size,
+// starting each chunk with up to overlap of the previous chunk's pieces.
+func (o options) merge(pieces []string, separator string) []string {
+	var chunks, current []string
+	for _, piece := range pieces {
+		if len(current) > 0 && o.length(join(current, piece, separator)) > o.chunkSize {
+			if chunk := strings.TrimSpace(strings.Join(current, separator)); chunk != "" {
+				chunks = append(chunks, chunk)
+			}
+			for len(current) > 0 &&
+				(o.length(strings.Join(current, separator)) > o.overlap ||
+					o.length(join(current, piece, separator)) > o.chunkSize) {
+				current = current[1:]
+			}
+		}
+		current = append(current, piece)
+	}
+
+	if chunk := strings.TrimSpace(strings.Join(current, separator)); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// join returns the pieces followed by next, joined with separator.
+func join(pieces []string, next, separator string) string {
+	return strings.Join(pieces, separator) + separator + next
+}
@@ -0,0 +1,164 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/textdiff"
+)
+
+// ChangeType describes how a message changed between two templates
+type ChangeType string
+
+const (
+	// Unchanged marks a message present in both templates with the same role and content
+	Unchanged ChangeType = "unchanged"
+
+	// Added marks a message only present in the new template
+	Added ChangeType = "added"
+
+	// Removed marks a message only present in the old template
+	Removed ChangeType = "removed"
+
+	// Changed marks a message whose content was edited in place
+	Changed ChangeType = "changed"
+)
+
+// MessageDiff describes the change of a single message.
+// Old is nil for added messages and New is nil for removed messages.
+// Content holds the line diff of the message content for changed messages.
+type MessageDiff struct {
+	Change  ChangeType      `json:"change"`
+	Old     message.Message `json:"old,omitempty"`
+	New     message.Message `json:"new,omitempty"`
+	Content []textdiff.Line `json:"content,omitempty"`
+}
+
+// TemplateDiff is a structured diff between two templates
+type TemplateDiff struct {
+	Messages []MessageDiff `json:"messages"`
+}
+
+// Diff compares two templates message by message. Messages are aligned by
+// role and content; a removed message directly replaced by an added message
+// with the same role is reported as changed, with a line diff of its content.
+//
+// Example:
+//
+//	d := template.Diff(v1, v2)
+//	if d.Changed() {
+//	  fmt.Print(d.String())
+//	}
+func Diff(a, b Template) TemplateDiff {
+	before, after := a.GetMessage(), b.GetMessage()
+
+	// Each message is encoded as a single line so the line diff aligns
+	// whole messages.
+	ops := textdiff.Lines(messageKeys(before), messageKeys(after))
+
+	var (
+		result         TemplateDiff
+		removed, added []message.Message
+		i, j           int
+	)
+
+	flush := func() {
+		n := min(len(removed), len(added))
+		for k := 0; k < n; k++ {
+			if removed[k].GetRole() != added[k].GetRole() {
+				n = k
+				break
+			}
+			result.Messages = append(result.Messages, MessageDiff{
+				Change:  Changed,
+				Old:     removed[k],
+				New:     added[k],
+				Content: textdiff.Lines(removed[k].GetContent(), added[k].GetContent()),
+			})
+		}
+		for _, m := range removed[n:] {
+			result.Messages = append(result.Messages, MessageDiff{Change: Removed, Old: m})
+		}
+		for _, m := range added[n:] {
+			result.Messages = append(result.Messages, MessageDiff{Change: Added, New: m})
+		}
+		removed, added = nil, nil
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case textdiff.Delete:
+			removed = append(removed, before[i])
+			i++
+		case textdiff.Insert:
+			added = append(added, after[j])
+			j++
+		default:
+			flush()
+			result.Messages = append(result.Messages, MessageDiff{
+				Change: Unchanged,
+				Old:    before[i],
+				New:    after[j],
+			})
+			i++
+			j++
+		}
+	}
+	flush()
+
+	return result
+}
+
+// Changed reports whether the templates differ
+func (d TemplateDiff) Changed() bool {
+	for _, m := range d.Messages {
+		if m.Change != Unchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the diff in a human readable form, listing every message
+// with a marker and showing content line diffs for changed messages
+func (d TemplateDiff) String() string {
+	var b strings.Builder
+	for i, m := range d.Messages {
+		switch m.Change {
+		case Added:
+			fmt.Fprintf(&b, "+ [%d] %s\n", i, m.New.GetRole())
+			writeIndented(&b, "+ ", m.New.GetContent())
+		case Removed:
+			fmt.Fprintf(&b, "- [%d] %s\n", i, m.Old.GetRole())
+			writeIndented(&b, "- ", m.Old.GetContent())
+		case Changed:
+			fmt.Fprintf(&b, "~ [%d] %s\n", i, m.New.GetRole())
+			writeIndented(&b, "  ", strings.TrimSuffix(textdiff.String(m.Content), "\n"))
+		default:
+			fmt.Fprintf(&b, "  [%d] %s\n", i, m.New.GetRole())
+		}
+	}
+	return b.String()
+}
+
+// messageKeys encodes every message as one line of text
+func messageKeys(messages []message.Message) string {
+	keys := make([]string, len(messages))
+	for i, m := range messages {
+		key, _ := json.Marshal([]string{string(m.GetRole()), m.GetContent()})
+		keys[i] = string(key)
+	}
+	return strings.Join(keys, "\n")
+}
+
+// writeIndented writes every line of text with a prefix
+func writeIndented(b *strings.Builder, prefix, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString("    ")
+		b.WriteString(prefix)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
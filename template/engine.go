@@ -0,0 +1,189 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// Engine renders the content of template messages. Templates use Go's
+// text/template by default; an engine replaces it for templates authored
+// in other dialects.
+type Engine interface {
+	// Render substitutes all variables in content.
+	Render(content string, v any) (string, error)
+
+	// Bind substitutes the variables present in vars and leaves all other
+	// placeholders intact.
+	Bind(content string, vars map[string]any) string
+}
+
+// variableLister is implemented by engines that can list the variables read
+// by content. Templates using other engines report no variables.
+type variableLister interface {
+	Variables(contents ...string) []message.Variable
+}
+
+// Option is a function type that modifies a template.
+type Option func(*template)
+
+// WithEngine renders the template with the given engine instead of text/template.
+//
+// Example:
+//
+//	tmpl := template.New([]message.Message{
+//	  message.FromUser("Summarize {{ document.title }} for {{audience}}."),
+//	}, template.WithEngine(template.NewMustacheEngine()))
+func WithEngine(engine Engine) Option {
+	return func(t *template) {
+		t.engine = engine
+	}
+}
+
+// New creates a new template from a list of messages with options.
+// Use From when no options are needed.
+//
+// Example:
+//
+//	tmpl := New(messages, WithEngine(NewSimpleEngine()))
+func New(messages []message.Message, options ...Option) Template {
+	t := template{Message: slices.Clone(messages)}
+	for _, option := range options {
+		option(&t)
+	}
+	return t
+}
+
+// placeholder matches {{name}} and {{ dotted.name }} interpolations.
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\s*\}\}`)
+
+// interpolationEngine implements mustache-style variable interpolation.
+type interpolationEngine struct {
+	strict bool
+}
+
+// NewMustacheEngine creates an engine for mustache-style {{name}} and
+// {{ user.name }} interpolation. Rendering is strict: a placeholder
+// without a value, or any other {{...}} tag such as a section, is an error.
+func NewMustacheEngine() Engine {
+	return interpolationEngine{strict: true}
+}
+
+// NewSimpleEngine creates an engine that replaces {{name}} placeholders with
+// their values and leaves unknown placeholders and other text untouched.
+func NewSimpleEngine() Engine {
+	return interpolationEngine{}
+}
+
+// Render substitutes all variables in content.
+func (e interpolationEngine) Render(content string, v any) (string, error) {
+	data, err := toData(v)
+	if err != nil {
+		return "", errorbank.NewTemplateError("data", "failed to convert template data", err)
+	}
+
+	var missing string
+	rendered := placeholder.ReplaceAllStringFunc(content, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		value, ok := lookup(data, name)
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return match
+		}
+		return fmt.Sprint(value)
+	})
+
+	if e.strict {
+		if missing != "" {
+			return "", errorbank.NewTemplateError(missing, "variable not found", nil)
+		}
+		if strings.Contains(placeholder.ReplaceAllString(content, ""), "{{") {
+			return "", errorbank.NewTemplateError("content", "unsupported tag", nil)
+		}
+	}
+
+	return rendered, nil
+}
+
+// Bind substitutes the variables present in vars and leaves all other
+// placeholders intact.
+func (e interpolationEngine) Bind(content string, vars map[string]any) string {
+	data, err := toData(vars)
+	if err != nil {
+		return content
+	}
+
+	return placeholder.ReplaceAllStringFunc(content, func(match string) string {
+		value, ok := lookup(data, placeholder.FindStringSubmatch(match)[1])
+		if !ok {
+			return match
+		}
+		return fmt.Sprint(value)
+	})
+}
+
+// Variables returns the variables referenced by placeholders in contents, in
+// order of first use. Dotted placeholders make their root variable an object.
+func (e interpolationEngine) Variables(contents ...string) []message.Variable {
+	var variables []message.Variable
+	index := map[string]int{}
+	for _, match := range placeholder.FindAllStringSubmatch(strings.Join(contents, "\n"), -1) {
+		name, _, nested := strings.Cut(match[1], ".")
+		typ := "string"
+		if nested {
+			typ = "object"
+		}
+
+		if i, ok := index[name]; ok {
+			if nested {
+				variables[i].Type = typ
+			}
+			continue
+		}
+		index[name] = len(variables)
+		variables = append(variables, message.Variable{Name: name, Type: typ})
+	}
+	return variables
+}
+
+// toData converts template data into a map. Structs are converted through
+// their JSON encoding, so placeholders use the JSON field names.
+func toData(v any) (map[string]any, error) {
+	if data, ok := v.(map[string]any); ok {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// lookup resolves a dotted name in nested maps.
+func lookup(data map[string]any, name string) (any, bool) {
+	var current any = data
+	for _, key := range strings.Split(name, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,45 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+)
+
+// Fingerprint returns a stable content hash of the rendered template, in the
+// form "sha256:<hex>". The hash covers the role, content, and attached parts
+// of every message, so two prompts share a fingerprint only if they send the
+// same bytes. It is recorded alongside outputs to trace them back to the
+// exact prompt that produced them, even after the template has changed.
+//
+// Example:
+//
+//	prompt := template.Invoke(vars)
+//	log.Printf("prompt=%s", prompt.Fingerprint())
+func (t template) Fingerprint() string {
+	h := sha256.New()
+	for _, m := range t.Message {
+		writeField(h, []byte(m.GetRole()))
+		writeField(h, []byte(m.GetContent()))
+		for _, part := range m.GetParts() {
+			writeField(h, []byte(part.Type))
+			writeField(h, []byte(part.Format))
+			writeField(h, []byte(part.Name))
+			writeField(h, part.Data)
+			writeField(h, []byte(part.FileID))
+		}
+		// Terminate the message so parts cannot be confused with the
+		// fields of the next message.
+		writeField(h, nil)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// writeField writes a length-prefixed field, which keeps the encoding unambiguous.
+func writeField(h hash.Hash, data []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+	h.Write(length[:])
+	h.Write(data)
+}
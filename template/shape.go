@@ -0,0 +1,125 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/pkg/errorbank"
+)
+
+// Rule checks the shape of a conversation, such as the order of roles.
+// It returns an error describing the first violation found.
+type Rule func(messages []message.Message) error
+
+// Profile is a named set of conversation-shape rules, typically matching
+// what a provider accepts.
+type Profile struct {
+	Name  string
+	Rules []Rule
+}
+
+var (
+	// ProfileOpenAI accepts any order of roles, like the OpenAI chat API.
+	ProfileOpenAI = Profile{Name: "openai"}
+
+	// ProfileAnthropic matches the Messages API: system messages only at
+	// the start, a user message first, and alternating user and assistant
+	// turns. A trailing assistant message is allowed as a response prefill.
+	ProfileAnthropic = Profile{Name: "anthropic", Rules: []Rule{SystemLeading, UserFirst, AlternatingTurns}}
+
+	// ProfileGemini matches generateContent: system messages only at the
+	// start, a user message first, and alternating user and model turns.
+	ProfileGemini = Profile{Name: "gemini", Rules: []Rule{SystemLeading, UserFirst, AlternatingTurns}}
+
+	// ProfileBedrock matches the Converse API, which has the same
+	// requirements as Anthropic.
+	ProfileBedrock = Profile{Name: "bedrock", Rules: []Rule{SystemLeading, UserFirst, AlternatingTurns}}
+
+	// ProfileStrict enforces the most portable shape: a leading system
+	// message, alternating turns starting with the user, and no trailing
+	// assistant message.
+	ProfileStrict = Profile{Name: "strict", Rules: []Rule{SystemFirst, SystemLeading, UserFirst, AlternatingTurns, NoTrailingAssistant}}
+)
+
+// SystemFirst requires the conversation to start with a system message.
+func SystemFirst(messages []message.Message) error {
+	if len(messages) == 0 || messages[0].GetRole() != message.RoleSystem {
+		return shapeError(0, messages, "conversation must start with a system message")
+	}
+	return nil
+}
+
+// SystemLeading requires system messages to come before all other messages.
+func SystemLeading(messages []message.Message) error {
+	seenTurn := false
+	for i, m := range messages {
+		if m.GetRole() != message.RoleSystem {
+			seenTurn = true
+			continue
+		}
+		if seenTurn {
+			return shapeError(i, messages, "system messages must come before user and assistant messages")
+		}
+	}
+	return nil
+}
+
+// UserFirst requires the first non-system message to be a user message.
+func UserFirst(messages []message.Message) error {
+	for i, m := range messages {
+		if m.GetRole() == message.RoleSystem {
+			continue
+		}
+		if m.GetRole() != message.RoleUser {
+			return shapeError(i, messages, "first non-system message must be a user message")
+		}
+		return nil
+	}
+	return nil
+}
+
+// AlternatingTurns requires user and assistant messages to alternate.
+// System messages are ignored.
+func AlternatingTurns(messages []message.Message) error {
+	var previous message.RoleType
+	for i, m := range messages {
+		role := m.GetRole()
+		if role == message.RoleSystem {
+			continue
+		}
+		if role == previous {
+			return shapeError(i, messages, fmt.Sprintf("consecutive %s messages; user and assistant messages must alternate", role))
+		}
+		previous = role
+	}
+	return nil
+}
+
+// NoTrailingAssistant requires the conversation not to end with an
+// assistant message, so there is something for the model to respond to.
+func NoTrailingAssistant(messages []message.Message) error {
+	last := len(messages) - 1
+	if last >= 0 && messages[last].GetRole() == message.RoleAssistant {
+		return shapeError(last, messages, "conversation must not end with an assistant message")
+	}
+	return nil
+}
+
+// validate applies every rule of the profile.
+func (p Profile) validate(messages []message.Message) error {
+	for _, rule := range p.Rules {
+		if err := rule(messages); err != nil {
+			return errorbank.NewTemplateError(p.Name, "conversation shape not accepted", err)
+		}
+	}
+	return nil
+}
+
+// shapeError reports a shape violation at the message with the given index.
+func shapeError(i int, messages []message.Message, msg string) error {
+	var role message.RoleType
+	if i < len(messages) {
+		role = messages[i].GetRole()
+	}
+	return errorbank.NewValidationError(fmt.Sprintf("message[%d]", i), msg, role)
+}
@@ -1,8 +1,8 @@
 package template
 
 import (
-	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/bpradana/tars/message"
 	"github.com/bpradana/tars/pkg/errorbank"
@@ -12,13 +12,21 @@ import (
 // It contains a sequence of messages that form a conversation context.
 type template struct {
 	Message []message.Message
+	engine  Engine
 }
 
 // Template defines the interface for conversation templates.
 // Templates can be used to create reusable conversation patterns
 // and perform variable substitution across multiple messages.
+//
+// Templates are immutable: they keep their own copy of the message list,
+// GetMessage returns a copy, and Invoke and Bind return new templates. A
+// template can therefore be shared by the goroutines of a server and
+// invoked concurrently.
 type Template interface {
-	// GetMessage returns the list of messages in the template
+	// GetMessage returns a copy of the list of messages in the template.
+	// Appending to or replacing elements of the returned slice does not
+	// affect the template.
 	GetMessage() []message.Message
 
 	// Invoke performs variable substitution on all messages in the template.
@@ -27,13 +35,30 @@ type Template interface {
 	// to placeholder names in the template (e.g., "{{.Name}}").
 	Invoke(v any) Template
 
+	// Bind substitutes the given variables and leaves all other placeholders
+	// intact, so the template can be completed later with Invoke.
+	Bind(vars map[string]any) Template
+
 	// ToJSON serializes the template to JSON string format.
+	// Options can add token annotations and pretty-print the output.
 	// Returns an empty string if serialization fails.
-	ToJSON() string
+	ToJSON(options ...message.JSONOption) string
+
+	// Variables returns the variables read by the template's messages, in
+	// order of first use, with their types inferred from how they are used.
+	Variables() []message.Variable
+
+	// Fingerprint returns a stable content hash of the template's messages,
+	// used to trace outputs back to the exact prompt that produced them.
+	Fingerprint() string
 
 	// Validate checks if the template is valid and returns an error if not.
-	// This method validates all messages in the template.
-	Validate() error
+	// This method validates all messages in the template and, when profiles
+	// are given, the shape of the conversation against each profile.
+	Validate(profiles ...Profile) error
+
+	// Clone returns a deep copy of the template and its messages.
+	Clone() Template
 }
 
 // From creates a new template from a sequence of messages.
@@ -47,13 +72,45 @@ type Template interface {
 //	)
 func From(messages ...message.Message) Template {
 	return template{
-		Message: messages,
+		Message: slices.Clone(messages),
+	}
+}
+
+// FromJSON reconstructs a template serialized by ToJSON, with or without
+// token annotations, so conversations can be persisted and sent between
+// services. The template is not validated; call Validate before use.
+//
+// Example:
+//
+//	conversation, err := FromJSON(stored)
+//	if err != nil {
+//	  return err
+//	}
+//	conversation = From(append(conversation.GetMessage(), message.FromUser(input))...)
+func FromJSON(data []byte) (Template, error) {
+	messages, err := message.TranscriptFromJSON(data)
+	if err != nil {
+		return nil, err
 	}
+	return template{Message: messages}, nil
 }
 
-// GetMessage returns the list of messages in the template
+// GetMessage returns a copy of the list of messages in the template
 func (t template) GetMessage() []message.Message {
-	return t.Message
+	return slices.Clone(t.Message)
+}
+
+// Clone returns a deep copy of the template and its messages.
+//
+// Example:
+//
+//	conversation := base.Clone()
+func (t template) Clone() Template {
+	messages := make([]message.Message, len(t.Message))
+	for i, m := range t.Message {
+		messages[i] = m.Clone()
+	}
+	return template{Message: messages, engine: t.engine}
 }
 
 // Invoke performs variable substitution on all messages in the template.
@@ -78,26 +135,95 @@ func (t template) Invoke(v any) Template {
 		Message: func() []message.Message {
 			messages := make([]message.Message, len(t.Message))
 			for i, m := range t.Message {
-				messages[i] = m.Invoke(v)
+				if t.engine == nil {
+					messages[i] = m.Invoke(v)
+					continue
+				}
+
+				content, err := t.engine.Render(m.GetContent(), v)
+				if err != nil {
+					messages[i] = m
+					continue
+				}
+				messages[i] = message.CopyWithContent(m, content)
 			}
 			return messages
 		}(),
+		engine: t.engine,
+	}
+}
+
+// Bind substitutes a subset of variables and leaves unknown placeholders
+// intact for a later Invoke. This allows layering configuration, such as
+// binding deployment-time constants once and per-request values later.
+//
+// Example:
+//
+//	support := template.Bind(map[string]any{"Company": "Acme"})
+//	result := support.Invoke(map[string]any{"Question": question})
+func (t template) Bind(vars map[string]any) Template {
+	if len(vars) == 0 {
+		return t
+	}
+
+	messages := make([]message.Message, len(t.Message))
+	for i, m := range t.Message {
+		if t.engine == nil {
+			messages[i] = m.Bind(vars)
+			continue
+		}
+		messages[i] = message.CopyWithContent(m, t.engine.Bind(m.GetContent(), vars))
 	}
+	return template{Message: messages, engine: t.engine}
 }
 
 // ToJSON serializes the template to JSON string format.
+// With message.WithTokenAnnotations the output is an object holding the
+// annotated messages and the estimated token total of the template.
 // Returns an empty string if serialization fails.
-func (t template) ToJSON() string {
-	json, err := json.Marshal(t.Message)
-	if err != nil {
-		return ""
+//
+// Example:
+//
+//	fmt.Println(template.ToJSON(message.WithTokenAnnotations(), message.WithIndent()))
+func (t template) ToJSON(options ...message.JSONOption) string {
+	return message.TranscriptJSON(t.Message, options...)
+}
+
+// Variables returns the variables read by the template's messages, in order
+// of first use. A variable used by several messages is listed once with the
+// most specific type inferred for it.
+//
+// Example:
+//
+//	for _, v := range template.Variables() {
+//	  fmt.Printf("%s (%s)\n", v.Name, v.Type)
+//	}
+func (t template) Variables() []message.Variable {
+	contents := make([]string, len(t.Message))
+	for i, m := range t.Message {
+		contents[i] = m.GetContent()
+	}
+
+	if t.engine == nil {
+		return message.Variables(contents...)
+	}
+	if lister, ok := t.engine.(variableLister); ok {
+		return lister.Variables(contents...)
 	}
-	return string(json)
+	return nil
 }
 
 // Validate checks if the template is valid and returns an error if not.
-// This method validates all messages in the template.
-func (t template) Validate() error {
+// This method validates all messages in the template. Profiles add
+// conversation-shape rules, such as role ordering, so that templates a
+// provider would reject are caught before any request is made.
+//
+// Example:
+//
+//	if err := template.Validate(ProfileAnthropic); err != nil {
+//	  log.Fatal(err)
+//	}
+func (t template) Validate(profiles ...Profile) error {
 	if len(t.Message) == 0 {
 		return errorbank.NewValidationError("messages", "template cannot be empty", t.Message)
 	}
@@ -108,5 +234,11 @@ func (t template) Validate() error {
 		}
 	}
 
+	for _, profile := range profiles {
+		if err := profile.validate(t.Message); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
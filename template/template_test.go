@@ -0,0 +1,93 @@
+package template_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bpradana/tars/message"
+	"github.com/bpradana/tars/template"
+)
+
+// newShared returns a template with parts and metadata, as shared by the
+// request handlers of a server.
+func newShared() template.Template {
+	return template.From(
+		message.FromSystem("You are a helpful assistant.", message.WithMetadata(map[string]any{"version": "v1"})),
+		message.FromUser("Hello {{.name}}, transcribe this.",
+			message.WithAudio([]byte("RIFF-audio"), "wav"),
+			message.WithMetadata(map[string]any{"tags": []any{"voice"}}),
+		),
+	)
+}
+
+// Run with -race: the template is read and derived from concurrently.
+func TestTemplateConcurrentUse(t *testing.T) {
+	shared := newShared()
+	want := shared.Fingerprint()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				shared.Invoke(map[string]any{"name": "Alice"}).GetMessage()
+				shared.Bind(map[string]any{"name": "Bob"}).ToJSON()
+
+				messages := shared.GetMessage()
+				messages[0] = message.FromSystem("replaced")
+				parts := messages[1].GetParts()
+				parts[0].Format = "mp3"
+				messages[1].GetMetadata()["tags"].([]any)[0] = "changed"
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := shared.Fingerprint(); got != want {
+		t.Errorf("Fingerprint() = %s after concurrent use, want %s", got, want)
+	}
+}
+
+func TestTemplateCopiesDoNotLeak(t *testing.T) {
+	shared := newShared()
+
+	messages := shared.GetMessage()
+	messages[0] = message.FromSystem("replaced")
+	messages = append(messages, message.FromUser("extra"))
+
+	parts := messages[1].GetParts()
+	parts[0].Format = "mp3"
+	parts[0].Name = "renamed"
+
+	metadata := messages[1].GetMetadata()
+	metadata["tags"].([]any)[0] = "changed"
+	metadata["added"] = true
+
+	clone := shared.Clone()
+	clone.GetMessage()[1].GetParts()[0].Data[0] = 'X'
+
+	got := shared.GetMessage()
+	if len(got) != 2 {
+		t.Fatalf("GetMessage() returned %d messages, want 2", len(got))
+	}
+	if content := got[0].GetContent(); content != "You are a helpful assistant." {
+		t.Errorf("system content = %q, want the original", content)
+	}
+
+	part := got[1].GetParts()[0]
+	if part.Format != "wav" || part.Name != "" {
+		t.Errorf("part = %+v, want the original format and name", part)
+	}
+	if string(part.Data) != "RIFF-audio" {
+		t.Errorf("part data = %q, want it unaffected by changes to a clone", part.Data)
+	}
+
+	gotMetadata := got[1].GetMetadata()
+	if tag := gotMetadata["tags"].([]any)[0]; tag != "voice" {
+		t.Errorf("metadata tag = %v, want voice", tag)
+	}
+	if _, ok := gotMetadata["added"]; ok {
+		t.Error("metadata key added to a copy leaked into the template")
+	}
+}
@@ -0,0 +1,112 @@
+package vectorstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bpradana/tars/pkg/errorbank"
+	"github.com/bpradana/tars/retriever"
+)
+
+// Memory is an in-memory Store that ranks records by cosine similarity.
+// It scans every record on each query, which is fast enough for prototypes
+// and small corpora.
+type Memory struct {
+	mu         sync.RWMutex
+	records    map[string]Record
+	dimensions int
+}
+
+// NewMemory creates an empty in-memory store. The vector dimensions are
+// fixed by the first record added.
+//
+// Example:
+//
+//	store := NewMemory()
+//	err := store.Add(ctx, Record{ID: "doc-1", Vector: vector, Content: text, Metadata: map[string]any{"tenant": "acme"}})
+//	matches, err := store.Query(ctx, queryVector, 5, Filter{"tenant": "acme"})
+func NewMemory() *Memory {
+	return &Memory{
+		records: make(map[string]Record),
+	}
+}
+
+// Add stores the given records, replacing records with the same ID.
+func (m *Memory) Add(ctx context.Context, records ...Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dimensions := m.dimensions
+	for _, record := range records {
+		if record.ID == "" {
+			return errorbank.NewValidationError("id", "cannot be empty", record.ID)
+		}
+		if len(record.Vector) == 0 {
+			return errorbank.NewValidationError("vector", "cannot be empty", record.ID)
+		}
+		if dimensions == 0 {
+			dimensions = len(record.Vector)
+		}
+		if len(record.Vector) != dimensions {
+			return errorbank.NewValidationError("vector", "dimensions do not match the store", len(record.Vector))
+		}
+	}
+
+	m.dimensions = dimensions
+	for _, record := range records {
+		m.records[record.ID] = record
+	}
+	return nil
+}
+
+// Query returns the k records most similar to the vector that match the filter.
+func (m *Memory) Query(ctx context.Context, vector []float64, k int, filter Filter) ([]Match, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.dimensions != 0 && len(vector) != m.dimensions {
+		return nil, errorbank.NewValidationError("vector", "dimensions do not match the store", len(vector))
+	}
+
+	matches := make([]Match, 0, len(m.records))
+	for _, record := range m.records {
+		if !filter.Matches(record.Metadata) {
+			continue
+		}
+		matches = append(matches, Match{
+			Record: record,
+			Score:  retriever.Cosine(vector, record.Vector),
+		})
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].ID < matches[b].ID
+	})
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// Delete removes the records with the given IDs.
+func (m *Memory) Delete(ctx context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		delete(m.records, id)
+	}
+	return nil
+}
+
+// Len returns the number of stored records.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.records)
+}
@@ -0,0 +1,85 @@
+// Package vectorstore stores embedding vectors with their content and
+// metadata and finds the records nearest to a query vector.
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Record is a vector stored together with the content it was computed from.
+type Record struct {
+	// ID uniquely identifies the record within a store.
+	ID string
+
+	// Vector is the embedding of the content.
+	Vector []float64
+
+	// Content is the text the vector was computed from.
+	Content string
+
+	// Metadata carries arbitrary information used for filtering (source, tenant, etc.).
+	Metadata map[string]any
+}
+
+// Match is a record returned by a query together with its similarity score.
+type Match struct {
+	Record
+	Score float64
+}
+
+// Filter restricts a query to records whose metadata matches every entry.
+// A value matches when it equals the metadata value; a slice value matches
+// when any of its elements does.
+//
+// Example:
+//
+//	filter := Filter{"tenant": "acme", "lang": []string{"en", "de"}}
+type Filter map[string]any
+
+// Store defines the interface for vector storage backends.
+// Query returns at most k matches ordered by decreasing similarity;
+// a k of zero or less returns every match.
+type Store interface {
+	Add(ctx context.Context, records ...Record) error
+	Query(ctx context.Context, vector []float64, k int, filter Filter) ([]Match, error)
+	Delete(ctx context.Context, ids ...string) error
+}
+
+// Matches reports whether metadata satisfies the filter.
+func (f Filter) Matches(metadata map[string]any) bool {
+	for key, want := range f {
+		got, ok := metadata[key]
+		if !ok || !matchValue(want, got) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchValue compares a filter value with a metadata value.
+func matchValue(want, got any) bool {
+	value := reflect.ValueOf(want)
+	if value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			if equal(value.Index(i).Interface(), got) {
+				return true
+			}
+		}
+		return false
+	}
+	return equal(want, got)
+}
+
+// equal compares two values, treating numbers of different types as equal
+// when their decimal representations match (e.g. int 3 and float64 3).
+func equal(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() {
+		return a == b
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}